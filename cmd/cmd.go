@@ -38,9 +38,20 @@ func NewRootCmd() *cobra.Command {
 	)
 
 	cmd.AddCommand(NewApplyCmd())
+	cmd.AddCommand(NewPlanCmd())
+	cmd.AddCommand(NewDiffCmd())
 	cmd.AddCommand(NewDestroyCmd())
+	cmd.AddCommand(NewRollbackCmd())
+	cmd.AddCommand(NewNodeCmd())
+	cmd.AddCommand(NewBackupCmd())
+	cmd.AddCommand(NewCertsCmd())
+	cmd.AddCommand(NewKeysCmd())
 	cmd.AddCommand(NewExportCmd())
 	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewHistoryCmd())
+	cmd.AddCommand(NewStatusCmd())
+	cmd.AddCommand(NewSshCmd())
+	cmd.AddCommand(NewExecCmd())
 
 	cmd.SetCompletionCommandGroupID("other")
 	cmd.SetHelpCommandGroupID("other")
@@ -8,7 +8,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const DefaultAction = "create"
+// DefaultAction is an empty action, which means that the action is detected
+// automatically from the configuration changes.
+const DefaultAction = ""
 
 var (
 	applyShort = "Create, scale or upgrade the cluster"
@@ -23,12 +25,24 @@ var (
 		> kubitect apply --config cluster.yaml --action upgrade
 
 		To scale an existing cluster, add or remove node instances in current cluster config and run:
-		> kubitect apply --config cluster.yaml --action scale`)
+		> kubitect apply --config cluster.yaml --action scale
+
+		If the action is omitted, it is detected automatically from the configuration changes.
+
+		To continue a failed apply from the phase that has failed, run:
+		> kubitect apply --config cluster.yaml --resume
+
+		To apply a plan previously saved by the plan command, run:
+		> kubitect apply --plan cluster.plan`)
 )
 
 type ApplyOptions struct {
-	Config string
-	Action string
+	Config      string
+	Action      string
+	Plan        string
+	Policy      string
+	Resume      bool
+	ForceUnlock bool
 
 	app.AppContextOptions
 }
@@ -49,12 +63,21 @@ func NewApplyCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVarP(&o.Config, "config", "c", "", "specify path to the cluster config file")
-	cmd.PersistentFlags().StringVarP(&o.Action, "action", "a", DefaultAction, "specify cluster action [create, upgrade, scale]")
+	cmd.PersistentFlags().StringVarP(&o.Action, "action", "a", DefaultAction, "specify cluster action [create, upgrade, scale, scale-upgrade] (detected automatically if omitted)")
+	cmd.PersistentFlags().StringVar(&o.Plan, "plan", "", "apply the plan saved by the plan command")
+	cmd.PersistentFlags().StringVar(&o.Policy, "policy", "", "specify path to the policy file with additional change rules")
+	cmd.PersistentFlags().BoolVar(&o.Resume, "resume", false, "continue the failed apply from the phase that has failed")
+	cmd.PersistentFlags().BoolVar(&o.ForceUnlock, "force-unlock", false, "remove the cluster lock held by another (possibly terminated) operation")
 	cmd.PersistentFlags().BoolVarP(&o.Local, "local", "l", false, "use a current directory as the cluster path")
 	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
 	cmd.PersistentFlags().BoolVar(&o.Debug, "debug", false, "enable debug messages")
+	addOutputFlag(cmd, &o.Output)
 
-	cmd.MarkPersistentFlagRequired("config")
+	cmd.MarkFlagsOneRequired("config", "plan")
+	cmd.MarkFlagsMutuallyExclusive("config", "plan")
+	cmd.MarkFlagsMutuallyExclusive("action", "plan")
+	cmd.MarkFlagsMutuallyExclusive("resume", "plan")
+	cmd.MarkFlagsMutuallyExclusive("policy", "plan")
 
 	cmd.RegisterFlagCompletionFunc("action", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return env.ProjectApplyActions[:], cobra.ShellCompDirectiveDefault
@@ -64,11 +87,49 @@ func NewApplyCmd() *cobra.Command {
 }
 
 func (o *ApplyOptions) Run() error {
+	if o.Plan != "" {
+		return o.applyPlan()
+	}
+
 	c, err := cluster.NewCluster(o.AppContext(), o.Config)
 
 	if err != nil {
 		return err
 	}
 
+	c.PolicyPath = o.Policy
+
+	if o.ForceUnlock {
+		if err := c.ForceUnlock(); err != nil {
+			return err
+		}
+	}
+
+	if o.Resume {
+		return c.Resume(o.Action)
+	}
+
 	return c.Apply(o.Action)
 }
+
+func (o *ApplyOptions) applyPlan() error {
+	pf, err := cluster.ReadPlanFile(o.Plan)
+	if err != nil {
+		return err
+	}
+
+	c, err := cluster.NewCluster(o.AppContext(), pf.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	c.PolicyPath = pf.PolicyPath
+
+	if o.ForceUnlock {
+		if err := c.ForceUnlock(); err != nil {
+			return err
+		}
+	}
+
+	return c.ApplyPlan(*pf)
+}
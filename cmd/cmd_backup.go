@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupShort = "Manage etcd backups of the cluster"
+	backupLong  = LongDesc(`
+		Create, list and restore backups of the cluster's etcd database.`)
+)
+
+func NewBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backup",
+		GroupID: "mgmt",
+		Short:   backupShort,
+		Long:    backupLong,
+	}
+
+	cmd.AddGroup(
+		&cobra.Group{
+			ID:    "main",
+			Title: "Commands:",
+		},
+	)
+
+	cmd.AddCommand(NewBackupCreateCmd())
+	cmd.AddCommand(NewBackupListCmd())
+	cmd.AddCommand(NewBackupRestoreCmd())
+
+	return cmd
+}
+
+// backupNames returns names of the given cluster's backups. Errors are
+// ignored, since names are only used for shell completion.
+func backupNames(ctx app.AppContext, clusterName string) []string {
+	c, err := findCluster(ctx, clusterName)
+	if err != nil {
+		return nil
+	}
+
+	backups, err := c.Backups()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, b := range backups {
+		names = append(names, b.Name)
+	}
+
+	return names
+}
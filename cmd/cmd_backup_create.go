@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupCreateShort = "Create an etcd backup of the cluster"
+	backupCreateLong  = LongDesc(`
+		Command backup create takes a snapshot of the etcd database on a control
+		plane node and downloads it into the backups directory of the cluster.
+
+		Along with the snapshot, the backup records the Kubernetes version and
+		manager of the cluster, etcd members, and the time of its creation. If
+		no name is given, the backup is named after the time of its creation.`)
+
+	backupCreateExample = Example(`
+		Create a backup of the cluster 'lake':
+		> kubitect backup create --cluster lake
+
+		Create a backup named 'before-upgrade':
+		> kubitect backup create --cluster lake --name before-upgrade`)
+)
+
+type BackupCreateOptions struct {
+	ClusterName string
+	Name        string
+
+	app.AppContextOptions
+}
+
+func NewBackupCreateCmd() *cobra.Command {
+	var o BackupCreateOptions
+
+	cmd := &cobra.Command{
+		Use:     "create",
+		GroupID: "main",
+		Short:   backupCreateShort,
+		Long:    backupCreateLong,
+		Example: backupCreateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	cmd.PersistentFlags().StringVar(&o.Name, "name", "", "name of the backup (default: time of creation)")
+
+	return cmd
+}
+
+func (o *BackupCreateOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	b, err := c.CreateBackup(o.Name)
+	if err != nil {
+		return err
+	}
+
+	ui.Printf(ui.INFO, "Backup '%s' of cluster '%s' has been created.\n", b.Name, c.Name)
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupListShort = "List etcd backups of the cluster"
+	backupListLong  = LongDesc(`
+		Command backup list lists backups of the cluster ordered by the time of
+		their creation.`)
+
+	backupListExample = Example(`
+		List backups of the cluster 'lake':
+		> kubitect backup list --cluster lake`)
+)
+
+type BackupListOptions struct {
+	ClusterName string
+
+	app.AppContextOptions
+}
+
+func NewBackupListCmd() *cobra.Command {
+	var o BackupListOptions
+
+	cmd := &cobra.Command{
+		Aliases: []string{"ls"},
+		Use:     "list",
+		GroupID: "main",
+		Short:   backupListShort,
+		Long:    backupListLong,
+		Example: backupListExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	addOutputFlag(cmd, &o.Output)
+
+	return cmd
+}
+
+func (o *BackupListOptions) Run() error {
+	c, err := findCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	backups, err := c.Backups()
+	if err != nil {
+		return err
+	}
+
+	if ui.Output().IsStructured() {
+		if backups == nil {
+			backups = []cluster.Backup{}
+		}
+
+		return ui.PrintObject("Backups", backups)
+	}
+
+	if len(backups) == 0 {
+		ui.Printf(ui.INFO, "Cluster '%s' has no backups.\n", o.ClusterName)
+		return nil
+	}
+
+	ui.Printf(ui.INFO, "Backups of cluster '%s':\n", o.ClusterName)
+
+	for _, b := range backups {
+		ui.Printf(ui.INFO, "  %s: %s (kubernetes: %s, members: %d, size: %s)\n",
+			b.Name, b.Created.Local().Format(time.DateTime), b.KubernetesVersion, len(b.Members), byteSize(b.Size))
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupRestoreShort = "Restore the cluster from an etcd backup"
+	backupRestoreLong  = LongDesc(`
+		Command backup restore restores the etcd database of the cluster from
+		the given backup.
+
+		The snapshot is uploaded to all control plane nodes, after which the
+		control plane is stopped on each of them and the snapshot is restored
+		on every etcd member. Finally, the control plane is started again.
+
+		The backup must have been created by the same Kubernetes manager that
+		manages the cluster.`)
+
+	backupRestoreExample = Example(`
+		Restore the cluster 'lake' from the backup 'before-upgrade':
+		> kubitect backup restore --cluster lake before-upgrade`)
+)
+
+type BackupRestoreOptions struct {
+	ClusterName string
+	Name        string
+
+	app.AppContextOptions
+}
+
+func NewBackupRestoreCmd() *cobra.Command {
+	var o BackupRestoreOptions
+
+	cmd := &cobra.Command{
+		Use:     "restore NAME",
+		GroupID: "main",
+		Short:   backupRestoreShort,
+		Long:    backupRestoreLong,
+		Example: backupRestoreExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Name = args[0]
+			return o.Run()
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return backupNames(o.AppContext(), o.ClusterName), cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
+
+	return cmd
+}
+
+func (o *BackupRestoreOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RestoreBackup(o.Name); err != nil {
+		return err
+	}
+
+	ui.Printf(ui.INFO, "Cluster '%s' has been restored from backup '%s'.\n", c.Name, o.Name)
+
+	return nil
+}
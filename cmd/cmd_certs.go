@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	certsShort = "Manage certificates of the cluster"
+	certsLong  = LongDesc(`
+		Check and renew certificates of the cluster's control plane.`)
+)
+
+func NewCertsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Aliases: []string{"certificates"},
+		Use:     "certs",
+		GroupID: "mgmt",
+		Short:   certsShort,
+		Long:    certsLong,
+	}
+
+	cmd.AddGroup(
+		&cobra.Group{
+			ID:    "main",
+			Title: "Commands:",
+		},
+	)
+
+	cmd.AddCommand(NewCertsCheckCmd())
+	cmd.AddCommand(NewCertsRenewCmd())
+
+	return cmd
+}
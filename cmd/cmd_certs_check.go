@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// certsExpiryWarning is the remaining validity of a certificate below
+// which a warning is shown.
+const certsExpiryWarning = 30 * 24 * time.Hour
+
+var (
+	certsCheckShort = "Show expiration of the cluster certificates"
+	certsCheckLong  = LongDesc(`
+		Command certs check connects to each control plane node over SSH and
+		lists the expiration dates of the control plane certificates, as well as
+		the expiration date of the client certificate embedded in the cluster's
+		kubeconfig.
+
+		A warning is shown for each certificate that expires within 30 days.`)
+
+	certsCheckExample = Example(`
+		Show expiration of certificates of the cluster 'lake':
+		> kubitect certs check --cluster lake
+
+		Show expiration of certificates in JSON format:
+		> kubitect certs check --cluster lake --output json`)
+)
+
+type CertsCheckOptions struct {
+	ClusterName string
+	Timeout     time.Duration
+
+	app.AppContextOptions
+}
+
+func NewCertsCheckCmd() *cobra.Command {
+	var o CertsCheckOptions
+
+	cmd := &cobra.Command{
+		Use:     "check",
+		GroupID: "main",
+		Short:   certsCheckShort,
+		Long:    certsCheckLong,
+		Example: certsCheckExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	cmd.PersistentFlags().DurationVar(&o.Timeout, "timeout", 10*time.Second, "maximum time to wait for the certificates of each node")
+	addOutputFlag(cmd, &o.Output)
+
+	return cmd
+}
+
+func (o *CertsCheckOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	cc := c.CheckCertificates(o.Timeout)
+
+	if ui.Output().IsStructured() {
+		return ui.PrintObject("Certificates", cc)
+	}
+
+	now := time.Now()
+
+	ui.Println(ui.INFO, certsTable(cc, now))
+
+	if cc.KubeconfigError != "" {
+		ui.Printf(ui.WARN, "Failed to read the kubeconfig certificate: %s\n", cc.KubeconfigError)
+	}
+
+	for _, n := range cc.Nodes {
+		if n.Error != "" {
+			ui.Printf(ui.WARN, "Failed to read certificates of node %q: %s\n", n.Node, n.Error)
+		}
+	}
+
+	expiring := 0
+	for _, crt := range allCertificates(cc) {
+		if crt.NotAfter.Sub(now) < certsExpiryWarning {
+			expiring++
+		}
+	}
+
+	if expiring > 0 {
+		ui.Printf(ui.WARN, "%d certificate(s) expire within %d days. Renew them using 'kubitect certs renew'.\n",
+			expiring, int(certsExpiryWarning.Hours()/24))
+	}
+
+	return nil
+}
+
+// allCertificates returns certificates of all nodes and the kubeconfig.
+func allCertificates(cc *cluster.ClusterCertificates) []cluster.Certificate {
+	var crts []cluster.Certificate
+	for _, n := range cc.Nodes {
+		crts = append(crts, n.Certificates...)
+	}
+
+	if cc.Kubeconfig != nil {
+		crts = append(crts, *cc.Kubeconfig)
+	}
+
+	return crts
+}
+
+// certsTable returns the certificates formatted as a table. Remaining
+// validity of certificates is calculated relative to the given time.
+func certsTable(cc *cluster.ClusterCertificates, now time.Time) string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tCERTIFICATE\tSUBJECT\tEXPIRES\tRESIDUAL")
+
+	row := func(node string, crt cluster.Certificate) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			node,
+			crt.Path,
+			orDash(crt.Subject),
+			crt.NotAfter.Local().Format(time.DateTime),
+			residual(crt.NotAfter.Sub(now)),
+		)
+	}
+
+	for _, n := range cc.Nodes {
+		for _, crt := range n.Certificates {
+			row(n.Node, crt)
+		}
+	}
+
+	if cc.Kubeconfig != nil {
+		row("-", *cc.Kubeconfig)
+	}
+
+	w.Flush()
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// residual formats the remaining validity of a certificate.
+func residual(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "expired"
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	certsRenewShort = "Renew certificates of the cluster"
+	certsRenewLong  = LongDesc(`
+		Command certs renew renews certificates of the control plane on each
+		control plane node, one node at a time.
+
+		For clusters managed by Kubespray, certificates are renewed using
+		kubeadm, after which the control plane components are restarted. For
+		K3s clusters, certificates are rotated by the K3s server, which is
+		restarted in the process.
+
+		Afterwards, the client certificate in the cluster's kubeconfig is
+		replaced with the renewed one.`)
+
+	certsRenewExample = Example(`
+		Renew certificates of the cluster 'lake':
+		> kubitect certs renew --cluster lake`)
+)
+
+type CertsRenewOptions struct {
+	ClusterName string
+
+	app.AppContextOptions
+}
+
+func NewCertsRenewCmd() *cobra.Command {
+	var o CertsRenewOptions
+
+	cmd := &cobra.Command{
+		Use:     "renew",
+		GroupID: "main",
+		Short:   certsRenewShort,
+		Long:    certsRenewLong,
+		Example: certsRenewExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
+
+	return cmd
+}
+
+func (o *CertsRenewOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RenewCertificates(); err != nil {
+		return err
+	}
+
+	ui.Printf(ui.INFO, "Certificates of cluster '%s' have been renewed.\n", c.Name)
+
+	return nil
+}
@@ -20,6 +20,7 @@ var (
 
 type DestroyOptions struct {
 	ClusterName string
+	ForceUnlock bool
 
 	app.AppContextOptions
 }
@@ -40,8 +41,10 @@ func NewDestroyCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	cmd.PersistentFlags().BoolVar(&o.ForceUnlock, "force-unlock", false, "remove the cluster lock held by another (possibly terminated) operation")
 	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
 	cmd.PersistentFlags().BoolVar(&o.Debug, "debug", false, "enable debug messages")
+	addOutputFlag(cmd, &o.Output)
 
 	cmd.MarkPersistentFlagRequired("cluster")
 
@@ -78,5 +81,11 @@ func (o *DestroyOptions) Run() error {
 		return fmt.Errorf("multiple clusters (%d) have been found with the same name (%s)", count, c.Name)
 	}
 
+	if o.ForceUnlock {
+		if err := c.ForceUnlock(); err != nil {
+			return err
+		}
+	}
+
 	return c.Destroy()
 }
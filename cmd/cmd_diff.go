@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffShort = "Show changes between the applied and the new configuration"
+	diffLong  = LongDesc(`
+		Compare new configuration file with the configuration applied to the
+		cluster and show the differences.
+
+		Default values are set in the new configuration file before the
+		comparison, the same way as when the configuration is applied. Unlike
+		the plan command, the changes are not evaluated against the apply rules.`)
+
+	diffExample = Example(`
+		Show changes of the cluster configuration:
+		> kubitect diff --config cluster.yaml
+
+		Show the whole configuration with changes highlighted:
+		> kubitect diff --config cluster.yaml --context
+
+		Print changes as a JSON Patch:
+		> kubitect diff --config cluster.yaml --json-patch`)
+)
+
+type DiffOptions struct {
+	Config    string
+	Context   bool
+	JsonPatch bool
+
+	app.AppContextOptions
+}
+
+func NewDiffCmd() *cobra.Command {
+	var o DiffOptions
+
+	cmd := &cobra.Command{
+		SuggestFor: []string{"compare"},
+		Use:        "diff",
+		GroupID:    "support",
+		Short:      diffShort,
+		Long:       diffLong,
+		Example:    diffExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.Config, "config", "c", "", "specify path to the cluster config file")
+	cmd.PersistentFlags().BoolVar(&o.Context, "context", false, "show unchanged fields along with the changes")
+	cmd.PersistentFlags().BoolVar(&o.JsonPatch, "json-patch", false, "print changes as a JSON Patch (RFC 6902)")
+	cmd.PersistentFlags().BoolVarP(&o.Local, "local", "l", false, "use a current directory as the cluster path")
+	addOutputFlag(cmd, &o.Output)
+
+	cmd.MarkPersistentFlagRequired("config")
+	cmd.MarkFlagsMutuallyExclusive("context", "json-patch")
+
+	return cmd
+}
+
+func (o *DiffOptions) Run() error {
+	c, err := cluster.NewCluster(o.AppContext(), o.Config)
+	if err != nil {
+		return err
+	}
+
+	if c.AppliedConfig == nil {
+		ui.Printf(ui.INFO, "Cluster %q has not been created yet. Showing the whole configuration...\n", c.Name)
+	}
+
+	if o.JsonPatch {
+		return o.printJsonPatch(c)
+	}
+
+	res, err := c.Diff()
+	if err != nil {
+		return err
+	}
+
+	if ui.Output().IsStructured() {
+		return ui.PrintObject("Changes", cluster.ConfigChanges(res))
+	}
+
+	if !res.HasChanges() {
+		ui.Println(ui.INFO, "No changes detected.")
+		return nil
+	}
+
+	opts := cmp.FormatOptions{
+		ShowDiffOnly:         !o.Context,
+		ShowColor:            ui.HasColor(),
+		ShowChangeTypePrefix: true,
+	}
+
+	ui.Println(ui.INFO, res.ToYaml(opts))
+
+	return nil
+}
+
+func (o *DiffOptions) printJsonPatch(c *cluster.Cluster) error {
+	patch, err := c.JsonPatch()
+	if err != nil {
+		return err
+	}
+
+	if ui.Output().IsStructured() {
+		return ui.PrintObject("JsonPatch", patch)
+	}
+
+	out, err := json.MarshalIndent(patch, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ui.Println(ui.INFO, string(out))
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	execShort = "Run a command on the cluster nodes"
+	execLong  = LongDesc(`
+		Command exec runs the given command over SSH on the selected nodes of the
+		cluster in parallel.
+
+		Each line of the command's output is prefixed with the name of the node
+		that produced it. Nodes are selected using selectors in format
+		'key=value', where the key is one of:
+		  - role: node role (master, worker, lb),
+		  - id: node ID,
+		  - name: node name (e.g. 'worker-1'),
+		  - host: name of the host on which the node is deployed,
+		  - label.<name>: value of the node label from the configuration.
+
+		Selectors with the same key are combined with a logical OR, while
+		selectors with different keys are combined with a logical AND. If no
+		selector is given, the command runs on all nodes.
+
+		The command exits with the highest exit code returned by the nodes. If
+		the command cannot be run on a node, for example because the node is
+		not reachable, the exit code 255 is used for that node.`)
+
+	execExample = Example(`
+		Show uptime of all nodes of the cluster 'lake':
+		> kubitect exec --cluster lake -- uptime
+
+		Restart the kubelet on worker nodes with ID 1 or 2:
+		> kubitect exec --cluster lake --nodes role=worker,id=1,id=2 -- sudo systemctl restart kubelet
+
+		Show disk usage of nodes with label 'disk=ssd', two nodes at a time:
+		> kubitect exec --cluster lake --nodes label.disk=ssd --concurrency 2 -- "df -h | grep /dev/vda"`)
+)
+
+type ExecOptions struct {
+	ClusterName string
+	Nodes       []string
+	Concurrency int
+	Command     string
+
+	app.AppContextOptions
+}
+
+func NewExecCmd() *cobra.Command {
+	var o ExecOptions
+
+	cmd := &cobra.Command{
+		Use:     "exec [flags] -- COMMAND",
+		GroupID: "support",
+		Short:   execShort,
+		Long:    execLong,
+		Example: execExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Command = strings.Join(args, " ")
+			return o.Run()
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	cmd.PersistentFlags().StringSliceVar(&o.Nodes, "nodes", nil, "select nodes on which the command is run (e.g. role=worker,id=1)")
+	cmd.PersistentFlags().IntVar(&o.Concurrency, "concurrency", 10, "maximum number of nodes on which the command runs at once")
+
+	cmd.RegisterFlagCompletionFunc("nodes", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var selectors []string
+		for _, n := range nodeNames(o.AppContext(), o.ClusterName) {
+			selectors = append(selectors, "name="+n)
+		}
+
+		selectors = append(selectors, "role=master", "role=worker", "role=lb")
+
+		return selectors, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func (o *ExecOptions) Run() error {
+	if o.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be a positive number, but got %d", o.Concurrency)
+	}
+
+	var selectors []cluster.NodeSelector
+	for _, s := range o.Nodes {
+		sel, err := cluster.ParseNodeSelector(s)
+		if err != nil {
+			return err
+		}
+
+		selectors = append(selectors, sel)
+	}
+
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	nodes := c.SelectNodes(selectors)
+	if len(nodes) == 0 {
+		return fmt.Errorf("no node of cluster %q matches the selectors %v", c.Name, o.Nodes)
+	}
+
+	rs := c.Exec(context.Background(), nodes, o.Command, o.Concurrency)
+
+	for _, r := range rs {
+		switch {
+		case r.Error != "":
+			ui.Printf(ui.WARN, "Failed to run the command on node %s: %s\n", r.Node, r.Error)
+		case r.ExitCode != 0:
+			ui.Printf(ui.WARN, "Command exited with code %d on node %s.\n", r.ExitCode, r.Node)
+		}
+	}
+
+	if code := rs.ExitCode(); code != 0 {
+		return ExitError{Code: code}
+	}
+
+	return nil
+}
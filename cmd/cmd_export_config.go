@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
 	"github.com/MusicDin/kubitect/pkg/utils/file"
 
 	"github.com/spf13/cobra"
@@ -42,6 +43,7 @@ func NewExportConfigCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	addOutputFlag(cmd, &o.Output)
 	cmd.MarkPersistentFlagRequired("cluster")
 
 	cmd.RegisterFlagCompletionFunc("cluster", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -90,6 +92,10 @@ func (o *ExportConfigOptions) Run() error {
 		return err
 	}
 
+	if ui.Output().IsStructured() {
+		return printYamlObject("Config", []byte(config))
+	}
+
 	fmt.Fprint(os.Stdout, config)
 
 	return nil
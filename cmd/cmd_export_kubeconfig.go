@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
 	"github.com/MusicDin/kubitect/pkg/utils/file"
 
 	"github.com/spf13/cobra"
@@ -45,6 +46,7 @@ func NewExportKcCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	addOutputFlag(cmd, &o.Output)
 	cmd.MarkPersistentFlagRequired("cluster")
 
 	cmd.RegisterFlagCompletionFunc("cluster", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -93,6 +95,10 @@ func (o *ExportKcOptions) Run() error {
 		return err
 	}
 
+	if ui.Output().IsStructured() {
+		return printYamlObject("Kubeconfig", []byte(kc))
+	}
+
 	fmt.Fprint(os.Stdout, kc)
 
 	return nil
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/MusicDin/kubitect/embed"
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
 
 	"github.com/spf13/cobra"
 )
@@ -25,6 +27,8 @@ var (
 
 type ExportPresetOptions struct {
 	PresetName string
+
+	app.AppContextOptions
 }
 
 func NewExportPresetCmd() *cobra.Command {
@@ -42,6 +46,7 @@ func NewExportPresetCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&o.PresetName, "name", "", "preset name")
+	addOutputFlag(cmd, &o.Output)
 	cmd.MarkPersistentFlagRequired("name")
 
 	cmd.RegisterFlagCompletionFunc("name", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -63,11 +68,18 @@ func NewExportPresetCmd() *cobra.Command {
 }
 
 func (o *ExportPresetOptions) Run() error {
+	// Initialize global ui.
+	o.AppContext()
+
 	p, err := embed.GetPreset(o.PresetName + ".yaml")
 	if err != nil {
 		return err
 	}
 
+	if ui.Output().IsStructured() {
+		return printYamlObject("Preset", p.Content)
+	}
+
 	fmt.Fprintln(os.Stdout, string(p.Content))
 	return nil
 }
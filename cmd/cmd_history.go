@@ -0,0 +1,90 @@
+package main
+
+import (
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyShort = "List applied configurations of the cluster"
+	historyLong  = LongDesc(`
+		Command history lists revisions of the cluster configuration. A new
+		revision is recorded each time the configuration is applied, along with
+		the apply action, Kubitect version and the outcome of the apply.`)
+
+	historyExample = Example(`
+		List revisions of the cluster 'lake':
+		> kubitect history --cluster lake
+
+		Compare revisions 2 and 3 of the cluster 'lake':
+		> kubitect history diff 2 3 --cluster lake`)
+)
+
+type HistoryOptions struct {
+	ClusterName string
+
+	app.AppContextOptions
+}
+
+func NewHistoryCmd() *cobra.Command {
+	var o HistoryOptions
+
+	cmd := &cobra.Command{
+		SuggestFor: []string{"revisions"},
+		Use:        "history",
+		GroupID:    "support",
+		Short:      historyShort,
+		Long:       historyLong,
+		Example:    historyExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	addOutputFlag(cmd, &o.Output)
+	cmd.MarkPersistentFlagRequired("cluster")
+
+	cmd.AddCommand(NewHistoryDiffCmd())
+
+	return cmd
+}
+
+func (o *HistoryOptions) Run() error {
+	c, err := findCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	revs, err := c.Revisions()
+	if err != nil {
+		return err
+	}
+
+	if ui.Output().IsStructured() {
+		if revs == nil {
+			revs = []cluster.Revision{}
+		}
+
+		return ui.PrintObject("Revisions", revs)
+	}
+
+	if len(revs) == 0 {
+		ui.Printf(ui.INFO, "Cluster '%s' has no recorded revisions.\n", o.ClusterName)
+		return nil
+	}
+
+	ui.Printf(ui.INFO, "Revisions of cluster '%s':\n", o.ClusterName)
+
+	for _, r := range revs {
+		ui.Printf(ui.INFO, "  %d: %s (action: %s, version: %s, outcome: %s)\n",
+			r.Number, r.Created.Local().Format(time.DateTime), r.Action, r.KubitectVersion, r.Outcome)
+	}
+
+	return nil
+}
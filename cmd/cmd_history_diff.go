@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyDiffShort = "Compare two revisions of the cluster configuration"
+	historyDiffLong  = LongDesc(`
+		Command history diff shows changes between configurations of two cluster
+		revisions. Revision numbers are listed by the history command.`)
+
+	historyDiffExample = Example(`
+		Show changes made by the revision 3 of the cluster 'lake':
+		> kubitect history diff 2 3 --cluster lake`)
+)
+
+type HistoryDiffOptions struct {
+	ClusterName string
+	From        int
+	To          int
+
+	app.AppContextOptions
+}
+
+func NewHistoryDiffCmd() *cobra.Command {
+	var o HistoryDiffOptions
+
+	cmd := &cobra.Command{
+		Use:     "diff FROM TO",
+		Short:   historyDiffShort,
+		Long:    historyDiffLong,
+		Example: historyDiffExample,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			o.From, err = strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid revision number %q", args[0])
+			}
+
+			o.To, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid revision number %q", args[1])
+			}
+
+			return o.Run()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	addOutputFlag(cmd, &o.Output)
+	cmd.MarkPersistentFlagRequired("cluster")
+
+	return cmd
+}
+
+func (o *HistoryDiffOptions) Run() error {
+	c, err := findCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.CompareRevisions(o.From, o.To)
+	if err != nil {
+		return err
+	}
+
+	if ui.Output().IsStructured() {
+		return ui.PrintObject("Changes", cluster.ConfigChanges(res))
+	}
+
+	if !res.HasChanges() {
+		ui.Printf(ui.INFO, "Revisions %d and %d have the same configuration.\n", o.From, o.To)
+		return nil
+	}
+
+	opts := cmp.FormatOptions{
+		ShowDiffOnly:         true,
+		ShowColor:            ui.HasColor(),
+		ShowChangeTypePrefix: true,
+	}
+
+	ui.Println(ui.INFO, res.ToYaml(opts))
+
+	return nil
+}
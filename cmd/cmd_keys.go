@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	keysShort = "Manage SSH keys of the cluster"
+	keysLong  = LongDesc(`
+		Manage the SSH key pair used to access the cluster nodes.`)
+)
+
+func NewKeysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "keys",
+		GroupID: "mgmt",
+		Short:   keysShort,
+		Long:    keysLong,
+	}
+
+	cmd.AddGroup(
+		&cobra.Group{
+			ID:    "main",
+			Title: "Commands:",
+		},
+	)
+
+	cmd.AddCommand(NewKeysRotateCmd())
+
+	return cmd
+}
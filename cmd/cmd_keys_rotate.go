@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	keysRotateShort = "Rotate SSH keys of the cluster"
+	keysRotateLong  = LongDesc(`
+		Command keys rotate replaces the SSH key pair used to access the
+		cluster nodes with a newly generated one.
+
+		The new public key is added to the authorized keys on each node and
+		verified to grant access, before the previous public key is removed
+		from all nodes. If adding nodes to known hosts is enabled in the node
+		template, their known hosts entries are refreshed as well.
+
+		The new key pair is of the type configured in the node template,
+		unless a different key type is given. Since the configuration of an
+		existing cluster cannot be changed, the key type of its nodes can only
+		be changed during the rotation.
+
+		If the rotation is interrupted, the new key pair is kept aside and
+		rerunning the command resumes the rotation with it.`)
+
+	keysRotateExample = Example(`
+		Rotate SSH keys of the cluster 'lake':
+		> kubitect keys rotate --cluster lake
+
+		Replace SSH keys of the cluster 'lake' with an ED25519 key pair:
+		> kubitect keys rotate --cluster lake --key-type ed25519
+
+		Replace SSH keys of the cluster 'lake' with a 3072-bit RSA key pair:
+		> kubitect keys rotate --cluster lake --key-type rsa --key-bits 3072`)
+)
+
+type KeysRotateOptions struct {
+	ClusterName string
+	KeyType     string
+	KeyBits     int
+
+	app.AppContextOptions
+}
+
+func NewKeysRotateCmd() *cobra.Command {
+	var o KeysRotateOptions
+
+	cmd := &cobra.Command{
+		Use:     "rotate",
+		GroupID: "main",
+		Short:   keysRotateShort,
+		Long:    keysRotateLong,
+		Example: keysRotateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+	cmd.PersistentFlags().StringVar(&o.KeyType, "key-type", "", "specify the type of the new SSH key pair [rsa, ecdsa, ed25519]")
+	cmd.PersistentFlags().IntVar(&o.KeyBits, "key-bits", 0, "specify the size of the new SSH key in bits")
+	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
+
+	cmd.RegisterFlagCompletionFunc("key-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		types := []string{string(config.RSA), string(config.ECDSA), string(config.ED25519)}
+		return types, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func (o *KeysRotateOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RotateSshKeys(config.SshKeyType(o.KeyType), o.KeyBits); err != nil {
+		return err
+	}
+
+	ui.Printf(ui.INFO, "SSH keys of cluster '%s' have been rotated.\n", c.Name)
+
+	return nil
+}
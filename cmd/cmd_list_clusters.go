@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
 	"github.com/MusicDin/kubitect/pkg/ui"
 
 	"github.com/spf13/cobra"
@@ -39,6 +40,8 @@ func NewListClustersCmd() *cobra.Command {
 		},
 	}
 
+	addOutputFlag(cmd, &o.Output)
+
 	return cmd
 }
 
@@ -51,6 +54,15 @@ func (o *ListClustersOptions) Run() error {
 		return err
 	}
 
+	if ui.Output().IsStructured() {
+		summaries := make([]cluster.MetaSummary, 0, len(clusters))
+		for _, c := range clusters {
+			summaries = append(summaries, c.Summary())
+		}
+
+		return ui.PrintObject("Clusters", summaries)
+	}
+
 	if len(clusters) == 0 {
 		ui.Println(ui.INFO, "No clusters initialized yet. Run 'kubitect apply' to create the cluster.")
 		return nil
@@ -37,15 +37,29 @@ func NewListPresetsCmd() *cobra.Command {
 		},
 	}
 
+	addOutputFlag(cmd, &o.Output)
+
 	return cmd
 }
 
 func (o *ListPresetsOptions) Run() error {
+	// Initialize global ui.
+	o.AppContext()
+
 	presets, err := embed.Presets()
 	if err != nil {
 		return err
 	}
 
+	if ui.Output().IsStructured() {
+		names := make([]string, 0, len(presets))
+		for _, p := range presets {
+			names = append(names, presetName(p.Name))
+		}
+
+		return ui.PrintObject("Presets", names)
+	}
+
 	ui.Println(ui.INFO, "Available presets:")
 	for _, p := range presets {
 		ui.Printf(ui.INFO, "- %s\n", presetName(p.Name))
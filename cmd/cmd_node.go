@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeShort = "Run maintenance operations on cluster nodes"
+	nodeLong  = LongDesc(`
+		Run maintenance operations on cluster nodes.`)
+)
+
+func NewNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "node",
+		GroupID: "mgmt",
+		Short:   nodeShort,
+		Long:    nodeLong,
+	}
+
+	cmd.AddGroup(
+		&cobra.Group{
+			ID:    "main",
+			Title: "Commands:",
+		},
+	)
+
+	cmd.AddCommand(NewNodeCordonCmd())
+	cmd.AddCommand(NewNodeDrainCmd())
+	cmd.AddCommand(NewNodeUncordonCmd())
+	cmd.AddCommand(NewNodeRebootCmd())
+	cmd.AddCommand(NewNodeReplaceCmd())
+
+	return cmd
+}
+
+// addNodeFlags adds the cluster flag, along with completions of the
+// cluster flag and the node argument.
+func addNodeFlags(cmd *cobra.Command, o *app.AppContextOptions, clusterName *string) {
+	addClusterFlag(cmd, o, clusterName)
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return nodeNames(o.AppContext(), *clusterName), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// addDrainFlags adds flags that configure how the node is drained.
+func addDrainFlags(cmd *cobra.Command, o *interfaces.DrainOptions) {
+	cmd.PersistentFlags().DurationVar(&o.Timeout, "timeout", 0, "maximum time to wait for the node to be drained (0 means no timeout)")
+	cmd.PersistentFlags().DurationVar(&o.GracePeriod, "grace-period", 0, "override the termination grace period of evicted pods (0 means the grace period of each pod is respected)")
+	cmd.PersistentFlags().BoolVar(&o.DisableEviction, "disable-eviction", false, "delete pods instead of evicting them, which bypasses PodDisruptionBudgets")
+	cmd.PersistentFlags().BoolVar(&o.DeleteEmptyDirData, "delete-emptydir-data", false, "evict pods using emptyDir volumes, whose data is deleted")
+	cmd.PersistentFlags().BoolVar(&o.Force, "force", false, "evict pods that are not managed by a controller")
+}
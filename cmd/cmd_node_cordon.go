@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeCordonShort = "Mark a node as unschedulable"
+	nodeCordonLong  = LongDesc(`
+		Command node cordon marks the given node as unschedulable, which
+		prevents new pods from being scheduled on it. Pods that are already
+		running on the node are not affected.`)
+
+	nodeCordonExample = Example(`
+		Cordon the worker node with ID 1 of the cluster 'lake':
+		> kubitect node cordon --cluster lake worker-1`)
+)
+
+type NodeCordonOptions struct {
+	ClusterName string
+	Node        string
+
+	app.AppContextOptions
+}
+
+func NewNodeCordonCmd() *cobra.Command {
+	var o NodeCordonOptions
+
+	cmd := &cobra.Command{
+		Use:     "cordon NODE",
+		GroupID: "main",
+		Short:   nodeCordonShort,
+		Long:    nodeCordonLong,
+		Example: nodeCordonExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Node = args[0]
+			return o.Run()
+		},
+	}
+
+	addNodeFlags(cmd, &o.AppContextOptions, &o.ClusterName)
+
+	return cmd
+}
+
+func (o *NodeCordonOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	return c.CordonNode(o.Node)
+}
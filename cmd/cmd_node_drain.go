@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeDrainShort = "Evict all pods from a node"
+	nodeDrainLong  = LongDesc(`
+		Command node drain marks the given node as unschedulable and evicts all
+		of its pods, except the ones managed by DaemonSets.
+
+		By default, pods are evicted with respect to their PodDisruptionBudgets,
+		and the drain fails if the node runs pods that use emptyDir volumes or
+		are not managed by a controller.`)
+
+	nodeDrainExample = Example(`
+		Drain the worker node with ID 1 of the cluster 'lake':
+		> kubitect node drain --cluster lake worker-1
+
+		Drain the node within 5 minutes, deleting the data of emptyDir volumes:
+		> kubitect node drain --cluster lake worker-1 --timeout 5m --delete-emptydir-data`)
+)
+
+type NodeDrainOptions struct {
+	ClusterName string
+	Node        string
+	Drain       interfaces.DrainOptions
+
+	app.AppContextOptions
+}
+
+func NewNodeDrainCmd() *cobra.Command {
+	var o NodeDrainOptions
+
+	cmd := &cobra.Command{
+		Use:     "drain NODE",
+		GroupID: "main",
+		Short:   nodeDrainShort,
+		Long:    nodeDrainLong,
+		Example: nodeDrainExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Node = args[0]
+			return o.Run()
+		},
+	}
+
+	addNodeFlags(cmd, &o.AppContextOptions, &o.ClusterName)
+	addDrainFlags(cmd, &o.Drain)
+
+	return cmd
+}
+
+func (o *NodeDrainOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	return c.DrainNode(o.Node, o.Drain)
+}
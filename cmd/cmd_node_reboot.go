@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeRebootShort = "Safely reboot a node"
+	nodeRebootLong  = LongDesc(`
+		Command node reboot drains the given node, reboots it, and waits for it
+		to boot. Once the node is ready again, it is marked as schedulable.
+
+		Load balancers are not part of the Kubernetes cluster, therefore they
+		are only rebooted.`)
+
+	nodeRebootExample = Example(`
+		Reboot the worker node with ID 1 of the cluster 'lake':
+		> kubitect node reboot --cluster lake worker-1
+
+		Reboot the node, deleting pods that use emptyDir volumes:
+		> kubitect node reboot --cluster lake worker-1 --delete-emptydir-data`)
+)
+
+type NodeRebootOptions struct {
+	ClusterName string
+	Node        string
+	Drain       interfaces.DrainOptions
+
+	app.AppContextOptions
+}
+
+func NewNodeRebootCmd() *cobra.Command {
+	var o NodeRebootOptions
+
+	cmd := &cobra.Command{
+		Use:     "reboot NODE",
+		GroupID: "main",
+		Short:   nodeRebootShort,
+		Long:    nodeRebootLong,
+		Example: nodeRebootExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Node = args[0]
+			return o.Run()
+		},
+	}
+
+	addNodeFlags(cmd, &o.AppContextOptions, &o.ClusterName)
+	addDrainFlags(cmd, &o.Drain)
+
+	return cmd
+}
+
+func (o *NodeRebootOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	return c.RebootNode(o.Node, o.Drain)
+}
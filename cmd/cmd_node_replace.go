@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeReplaceShort = "Recreate a node in place"
+	nodeReplaceLong  = LongDesc(`
+		Command node replace destroys the given node and recreates it with the
+		same ID, IP and MAC address.
+
+		The node is first drained and removed from the Kubernetes cluster.
+		Afterwards, only its virtual machine is recreated, while data disks
+		are preserved. The new node is then joined to the cluster and the
+		labels and taints of the replaced node are restored.
+
+		The applied configuration of the cluster remains unchanged.`)
+
+	nodeReplaceExample = Example(`
+		Replace the worker node with ID 3 of the cluster 'lake':
+		> kubitect node replace --cluster lake worker-3
+
+		Replace the node without asking for confirmation:
+		> kubitect node replace --cluster lake worker-3 --auto-approve`)
+)
+
+type NodeReplaceOptions struct {
+	ClusterName string
+	Node        string
+	Drain       interfaces.DrainOptions
+
+	app.AppContextOptions
+}
+
+func NewNodeReplaceCmd() *cobra.Command {
+	var o NodeReplaceOptions
+
+	cmd := &cobra.Command{
+		Use:     "replace NODE",
+		GroupID: "main",
+		Short:   nodeReplaceShort,
+		Long:    nodeReplaceLong,
+		Example: nodeReplaceExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Node = args[0]
+			return o.Run()
+		},
+	}
+
+	addNodeFlags(cmd, &o.AppContextOptions, &o.ClusterName)
+	addDrainFlags(cmd, &o.Drain)
+
+	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
+
+	return cmd
+}
+
+func (o *NodeReplaceOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	return c.ReplaceNode(o.Node, o.Drain)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeUncordonShort = "Mark a node as schedulable"
+	nodeUncordonLong  = LongDesc(`
+		Command node uncordon waits for the given node to become ready and marks
+		it as schedulable.`)
+
+	nodeUncordonExample = Example(`
+		Uncordon the worker node with ID 1 of the cluster 'lake':
+		> kubitect node uncordon --cluster lake worker-1`)
+)
+
+type NodeUncordonOptions struct {
+	ClusterName string
+	Node        string
+
+	app.AppContextOptions
+}
+
+func NewNodeUncordonCmd() *cobra.Command {
+	var o NodeUncordonOptions
+
+	cmd := &cobra.Command{
+		Use:     "uncordon NODE",
+		GroupID: "main",
+		Short:   nodeUncordonShort,
+		Long:    nodeUncordonLong,
+		Example: nodeUncordonExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Node = args[0]
+			return o.Run()
+		},
+	}
+
+	addNodeFlags(cmd, &o.AppContextOptions, &o.ClusterName)
+
+	return cmd
+}
+
+func (o *NodeUncordonOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	return c.UncordonNode(o.Node)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the plan command.
+const (
+	PlanExitCodeNoChanges = 0
+	PlanExitCodeError     = 1
+	PlanExitCodeChanges   = 2
+)
+
+var (
+	planShort = "Preview changes of the cluster"
+	planLong  = LongDesc(`
+		Compare new configuration file with the applied one and show changes that
+		would be made by the apply command, without modifying the cluster.
+
+		Command exits with code 0 if there are no changes, with code 1 if an error
+		occurs or the configuration contains disallowed changes, and with code 2
+		if the configuration contains changes that can be applied.
+
+		The plan can be saved to a file and later applied exactly as planned
+		using the apply command.`)
+
+	planExample = Example(`
+		Preview changes of the cluster:
+		> kubitect plan --config cluster.yaml
+
+		Preview changes for the scale action:
+		> kubitect plan --config cluster.yaml --action scale
+
+		Explain which rules match the detected changes:
+		> kubitect plan --config cluster.yaml --explain
+
+		Save the plan to a file and apply it:
+		> kubitect plan --config cluster.yaml --out cluster.plan
+		> kubitect apply --plan cluster.plan`)
+)
+
+type PlanOptions struct {
+	Config  string
+	Action  string
+	Out     string
+	Policy  string
+	Explain bool
+
+	app.AppContextOptions
+}
+
+func NewPlanCmd() *cobra.Command {
+	var o PlanOptions
+
+	cmd := &cobra.Command{
+		SuggestFor: []string{"preview"},
+		Use:        "plan",
+		GroupID:    "mgmt",
+		Short:      planShort,
+		Long:       planLong,
+		Example:    planExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.Config, "config", "c", "", "specify path to the cluster config file")
+	cmd.PersistentFlags().StringVarP(&o.Action, "action", "a", DefaultAction, "specify cluster action [create, upgrade, scale, scale-upgrade] (detected automatically if omitted)")
+	cmd.PersistentFlags().StringVar(&o.Out, "out", "", "save the plan to the given file")
+	cmd.PersistentFlags().StringVar(&o.Policy, "policy", "", "specify path to the policy file with additional change rules")
+	cmd.PersistentFlags().BoolVar(&o.Explain, "explain", false, "explain which rules match each detected change")
+	cmd.PersistentFlags().BoolVarP(&o.Local, "local", "l", false, "use a current directory as the cluster path")
+	cmd.PersistentFlags().BoolVar(&o.Debug, "debug", false, "enable debug messages")
+	addOutputFlag(cmd, &o.Output)
+
+	cmd.MarkPersistentFlagRequired("config")
+
+	cmd.RegisterFlagCompletionFunc("action", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return env.ProjectApplyActions[:], cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+func (o *PlanOptions) Run() error {
+	// Infrastructure plan is always shown.
+	o.ShowTerraformPlan = true
+
+	c, err := cluster.NewCluster(o.AppContext(), o.Config)
+	if err != nil {
+		return err
+	}
+
+	c.PolicyPath = o.Policy
+	c.Explain = o.Explain
+
+	p, err := c.Plan(o.Action)
+	if p != nil {
+		if err := ui.PrintObject("Plan", p.Summary()); err != nil {
+			return ExitError{Code: PlanExitCodeError, Err: err}
+		}
+	}
+
+	if err != nil {
+		return ExitError{Code: PlanExitCodeError, Err: err}
+	}
+
+	if o.Out != "" {
+		if err := p.File().Write(o.Out); err != nil {
+			return ExitError{Code: PlanExitCodeError, Err: err}
+		}
+
+		ui.Printf(ui.INFO, "Plan has been saved to %q.\n", o.Out)
+	}
+
+	if p.HasChanges() {
+		return ExitError{Code: PlanExitCodeChanges}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackShort = "Revert the cluster configuration to an earlier revision"
+	rollbackLong  = LongDesc(`
+		Command rollback applies the configuration of an earlier cluster revision.
+
+		The configuration of the revision is compared with the applied one, and the
+		detected changes are evaluated with the same rules as with the apply
+		command. Changes that cannot be reverted, such as a Kubernetes downgrade,
+		are therefore rejected.`)
+
+	rollbackExample = Example(`
+		List revisions of the cluster 'lake':
+		> kubitect history --cluster lake
+
+		Revert the cluster 'lake' to the configuration of the revision 2:
+		> kubitect rollback --cluster lake --to 2`)
+)
+
+type RollbackOptions struct {
+	ClusterName string
+	To          int
+	Policy      string
+	ForceUnlock bool
+
+	app.AppContextOptions
+}
+
+func NewRollbackCmd() *cobra.Command {
+	var o RollbackOptions
+
+	cmd := &cobra.Command{
+		SuggestFor: []string{"revert", "undo"},
+		Use:        "rollback",
+		GroupID:    "mgmt",
+		Short:      rollbackShort,
+		Long:       rollbackLong,
+		Example:    rollbackExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	cmd.PersistentFlags().IntVar(&o.To, "to", 0, "specify the revision to roll back to")
+	cmd.PersistentFlags().StringVar(&o.Policy, "policy", "", "specify path to the policy file with additional change rules")
+	cmd.PersistentFlags().BoolVar(&o.ForceUnlock, "force-unlock", false, "remove the cluster lock held by another (possibly terminated) operation")
+	cmd.PersistentFlags().BoolVar(&o.AutoApprove, "auto-approve", false, "automatically approve any user permission requests")
+	cmd.PersistentFlags().BoolVar(&o.Debug, "debug", false, "enable debug messages")
+	addOutputFlag(cmd, &o.Output)
+
+	cmd.MarkPersistentFlagRequired("cluster")
+	cmd.MarkPersistentFlagRequired("to")
+
+	return cmd
+}
+
+func (o *RollbackOptions) Run() error {
+	meta, err := findCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	r, err := meta.Revision(o.To)
+	if err != nil {
+		return err
+	}
+
+	if r.Outcome == cluster.REVISION_FAILED {
+		return fmt.Errorf("cannot roll back to revision %d, because its apply has failed", r.Number)
+	}
+
+	// Local clusters are located in a different directory.
+	o.Local = meta.Local
+
+	c, err := cluster.NewCluster(o.AppContext(), meta.RevisionConfigPath(o.To))
+	if err != nil {
+		return err
+	}
+
+	c.PolicyPath = o.Policy
+
+	if o.ForceUnlock {
+		if err := c.ForceUnlock(); err != nil {
+			return err
+		}
+	}
+
+	return c.Apply(DefaultAction)
+}
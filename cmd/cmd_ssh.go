@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshShort = "Open an interactive shell on a cluster node"
+	sshLong  = LongDesc(`
+		Command ssh opens an interactive shell on the given node of the cluster.
+
+		The node is accessed over SSH as the user configured in the node
+		template, using the cluster's SSH key. The node can be referenced either
+		by its full name (for example, 'lake-worker-1') or without the cluster
+		name prefix (for example, 'worker-1').`)
+
+	sshExample = Example(`
+		Open a shell on the worker node with ID 1 of the cluster 'lake':
+		> kubitect ssh --cluster lake worker-1`)
+)
+
+type SshOptions struct {
+	ClusterName string
+	Node        string
+
+	app.AppContextOptions
+}
+
+func NewSshCmd() *cobra.Command {
+	var o SshOptions
+
+	cmd := &cobra.Command{
+		Use:     "ssh NODE",
+		GroupID: "support",
+		Short:   sshShort,
+		Long:    sshLong,
+		Example: sshExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.Node = args[0]
+			return o.Run()
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return nodeNames(o.AppContext(), o.ClusterName), cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	addClusterFlag(cmd, &o.AppContextOptions, &o.ClusterName)
+
+	return cmd
+}
+
+func (o *SshOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	err = c.Shell(o.Node)
+
+	// Propagate the exit code of the remote shell.
+	if code, ok := exec.ExitStatus(err); ok {
+		return ExitError{Code: code}
+	}
+
+	return err
+}
+
+// nodeNames returns names of the nodes of the given cluster, which are
+// used for the shell completion. If the cluster cannot be opened, no
+// names are returned.
+func nodeNames(ctx app.AppContext, clusterName string) []string {
+	if clusterName == "" {
+		return nil
+	}
+
+	c, err := openCluster(ctx, clusterName)
+	if err != nil {
+		return nil
+	}
+
+	return c.NodeNames()
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/cluster"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusShort = "Show live status of the cluster nodes"
+	statusLong  = LongDesc(`
+		Command status connects to each node of the cluster over SSH and reports
+		whether the node is reachable and the state of its services.
+
+		For master and worker nodes, the state of the kubelet and container
+		runtime is shown, along with the Ready condition and version of the
+		corresponding Kubernetes node. For load balancer nodes, the state of
+		HAProxy and Keepalived is shown, as well as which node holds the
+		virtual IP (VIP).`)
+
+	statusExample = Example(`
+		Show status of the cluster 'lake':
+		> kubitect status --cluster lake
+
+		Show status of the cluster 'lake' in JSON format:
+		> kubitect status --cluster lake --output json`)
+)
+
+type StatusOptions struct {
+	ClusterName string
+	Timeout     time.Duration
+
+	app.AppContextOptions
+}
+
+func NewStatusCmd() *cobra.Command {
+	var o StatusOptions
+
+	cmd := &cobra.Command{
+		SuggestFor: []string{"health", "state"},
+		Use:        "status",
+		GroupID:    "support",
+		Short:      statusShort,
+		Long:       statusLong,
+		Example:    statusExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&o.ClusterName, "cluster", "", "specify the cluster to be used")
+	cmd.PersistentFlags().DurationVar(&o.Timeout, "timeout", 10*time.Second, "maximum time to wait for the status of each node")
+	addOutputFlag(cmd, &o.Output)
+
+	cmd.MarkPersistentFlagRequired("cluster")
+
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	c, err := openCluster(o.AppContext(), o.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	s := c.Status(o.Timeout)
+
+	if ui.Output().IsStructured() {
+		return ui.PrintObject("Status", s)
+	}
+
+	ui.Println(ui.INFO, statusTable(s))
+
+	if s.VIP != "" {
+		holder := s.VIPHolder
+		if holder == "" {
+			holder = "none"
+		}
+
+		ui.Printf(ui.INFO, "Virtual IP %s is held by: %s\n", s.VIP, holder)
+	}
+
+	if s.KubernetesError != "" {
+		ui.Printf(ui.WARN, "Failed to retrieve Kubernetes nodes: %s\n", s.KubernetesError)
+	}
+
+	for _, n := range s.Nodes {
+		if n.Error != "" {
+			ui.Printf(ui.WARN, "Node %q is unreachable: %s\n", n.Name, n.Error)
+		}
+	}
+
+	return nil
+}
+
+// statusTable returns the status of the cluster nodes formatted as a table.
+func statusTable(s *cluster.ClusterStatus) string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tIP\tREACHABLE\tKUBELET\tRUNTIME\tREADY\tVERSION\tHAPROXY\tKEEPALIVED\tVIP")
+
+	for _, n := range s.Nodes {
+		vip := ""
+		if n.HoldsVIP {
+			vip = "*"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			n.Name,
+			n.IP,
+			yesNo(n.Reachable),
+			orDash(n.Kubelet),
+			orDash(n.ContainerRuntime),
+			orDash(n.Ready),
+			orDash(n.Version),
+			orDash(n.HAProxy),
+			orDash(n.Keepalived),
+			orDash(vip),
+		)
+	}
+
+	w.Flush()
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
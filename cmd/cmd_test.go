@@ -57,3 +57,27 @@ func TestExportCmd_Help(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, out, exportLong)
 }
+
+func TestBackupCmd_Help(t *testing.T) {
+	out, err := Execute(t, NewBackupCmd)
+	require.NoError(t, err)
+	assert.Contains(t, out, backupLong)
+}
+
+func TestCertsCmd_Help(t *testing.T) {
+	out, err := Execute(t, NewCertsCmd)
+	require.NoError(t, err)
+	assert.Contains(t, out, certsLong)
+}
+
+func TestKeysCmd_Help(t *testing.T) {
+	out, err := Execute(t, NewKeysCmd)
+	require.NoError(t, err)
+	assert.Contains(t, out, keysLong)
+}
+
+func TestNodeCmd_Help(t *testing.T) {
+	out, err := Execute(t, NewNodeCmd)
+	require.NoError(t, err)
+	assert.Contains(t, out, nodeLong)
+}
@@ -1,15 +1,46 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/MusicDin/kubitect/pkg/ui"
 )
 
+// ExitError is returned by commands that need to terminate the application
+// with a specific exit code. If the wrapped error is nil, nothing is printed.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e ExitError) Error() string {
+	if e.Err == nil {
+		return ""
+	}
+
+	return e.Err.Error()
+}
+
+func (e ExitError) Unwrap() error {
+	return e.Err
+}
+
 func main() {
 	err := NewRootCmd().Execute()
-	if err != nil {
-		ui.PrintBlockE(err)
-		os.Exit(1)
+	if err == nil {
+		return
+	}
+
+	var exitErr ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Err != nil {
+			ui.PrintBlockE(exitErr.Err)
+		}
+
+		os.Exit(exitErr.Code)
 	}
+
+	ui.PrintBlockE(err)
+	os.Exit(1)
 }
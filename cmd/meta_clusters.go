@@ -41,6 +41,39 @@ func (cs MetaClusters) CountByName(name string) int {
 	return i
 }
 
+// findCluster returns the cluster with the given name. It fails if the
+// cluster does not exist or if multiple clusters share the same name.
+func findCluster(ctx app.AppContext, name string) (*cluster.ClusterMeta, error) {
+	cs, err := AllClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := cs.FindByName(name)
+
+	if c == nil {
+		return nil, fmt.Errorf("cluster '%s' does not exist", name)
+	}
+
+	count := cs.CountByName(name)
+
+	if count > 1 {
+		return nil, fmt.Errorf("multiple clusters (%d) have been found with the name '%s'", count, name)
+	}
+
+	return c, nil
+}
+
+// openCluster opens the existing cluster with the given name.
+func openCluster(ctx app.AppContext, name string) (*cluster.Cluster, error) {
+	meta, err := findCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.OpenCluster(*meta)
+}
+
 // AllClusters returns list of clusters meta from both global (project)
 // and local clusters directory (if working directory is a Kubitect project).
 func AllClusters(ctx app.AppContext) (MetaClusters, error) {
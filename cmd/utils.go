@@ -4,6 +4,12 @@ import (
 	"fmt"
 	"path"
 	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // LongDesc trims alls leading and trailing spaces from each line.
@@ -48,3 +54,86 @@ func presetName(fPath string) string {
 
 	return base[:len(base)-len(ext)]
 }
+
+// byteSize formats the given number of bytes in a human readable form
+// using binary units.
+func byteSize(b int64) string {
+	const unit = 1024
+
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+// outputFormatValue is a flag value that accepts only supported output
+// formats.
+type outputFormatValue ui.OutputFormat
+
+func (v *outputFormatValue) String() string {
+	return ui.OutputFormat(*v).String()
+}
+
+func (v *outputFormatValue) Set(s string) error {
+	f, err := ui.ToOutputFormat(s)
+	if err != nil {
+		return err
+	}
+
+	*v = outputFormatValue(f)
+	return nil
+}
+
+func (v *outputFormatValue) Type() string {
+	return "string"
+}
+
+// addOutputFlag adds the output format flag to the given command.
+func addOutputFlag(cmd *cobra.Command, format *ui.OutputFormat) {
+	cmd.PersistentFlags().VarP((*outputFormatValue)(format), "output", "o", "output format [text, json, yaml]")
+
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var formats []string
+		for _, f := range ui.OutputFormats {
+			formats = append(formats, string(f))
+		}
+
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// addClusterFlag adds the required cluster flag along with its completion.
+func addClusterFlag(cmd *cobra.Command, o *app.AppContextOptions, clusterName *string) {
+	cmd.PersistentFlags().StringVar(clusterName, "cluster", "", "specify the cluster to be used")
+
+	cmd.MarkPersistentFlagRequired("cluster")
+
+	cmd.RegisterFlagCompletionFunc("cluster", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		clusters, err := AllClusters(o.AppContext())
+
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return clusters.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// printYamlObject prints the given YAML document as a structured object of
+// the given kind.
+func printYamlObject(kind string, content []byte) error {
+	var data any
+
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("parse %s: %v", strings.ToLower(kind), err)
+	}
+
+	return ui.PrintObject(kind, data)
+}
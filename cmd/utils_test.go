@@ -30,3 +30,11 @@ func TestPresetName(t *testing.T) {
 	assert.Equal(t, "test", presetName("test/test.yml"))
 	assert.Equal(t, "test.test", presetName("test.test.yml"))
 }
+
+func TestByteSize(t *testing.T) {
+	assert.Equal(t, "0 B", byteSize(0))
+	assert.Equal(t, "1023 B", byteSize(1023))
+	assert.Equal(t, "1.0 KiB", byteSize(1024))
+	assert.Equal(t, "1.5 MiB", byteSize(1536*1024))
+	assert.Equal(t, "2.0 GiB", byteSize(2*1024*1024*1024))
+}
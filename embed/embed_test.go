@@ -1,10 +1,12 @@
 package embed
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/MusicDin/kubitect/pkg/env"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetTemplate(t *testing.T) {
@@ -41,6 +43,27 @@ func TestGetResources_Invalid(t *testing.T) {
 	assert.ErrorContains(t, err, "terraform/modules: is a directory")
 }
 
+// TestVmModule_IgnoresSshKeyChanges ensures that existing virtual
+// machines are not recreated when the SSH key pair is rotated.
+func TestVmModule_IgnoresSshKeyChanges(t *testing.T) {
+	res, err := GetResource("terraform/modules/vm/vm.tf")
+	require.NoError(t, err)
+
+	block := func(header string) string {
+		_, body, ok := strings.Cut(string(res.Content), header)
+		require.True(t, ok, header)
+
+		body, _, _ = strings.Cut(body, "\n}\n")
+		return body
+	}
+
+	disk := block(`resource "libvirt_cloudinit_disk" "cloud_init" {`)
+	assert.Regexp(t, `ignore_changes\s*=\s*\[[^\]]*\buser_data\b`, disk)
+
+	domain := block(`resource "libvirt_domain" "vm_domain" {`)
+	assert.Regexp(t, `ignore_changes\s*=\s*\[[^\]]*\bcloudinit\b`, domain)
+}
+
 func TestPresets(t *testing.T) {
 	expect := []string{
 		"presets/minimal.yaml",
@@ -29,6 +29,12 @@ resource "libvirt_cloudinit_disk" "cloud_init" {
       vm_dns_list       = length(var.vm_dns) == 0 ? var.network_gateway : join(", ", var.vm_dns)
       vm_cidr           = var.vm_ip == null ? "" : "${var.vm_ip}/${split("/", var.network_cidr)[1]}"
   })
+
+  # User data of an existing VM is applied only on its first boot, and
+  # SSH keys are rotated on running VMs by Kubitect.
+  lifecycle {
+    ignore_changes = [user_data]
+  }
 }
 
 #================================
@@ -42,6 +48,11 @@ resource "libvirt_volume" "vm_main_disk" {
   base_volume_id = var.base_volume_id
   size           = var.vm_main_disk_size * pow(1024, 3) # GiB -> B
   format         = "qcow2"
+
+  # Main disk of an existing VM is resized in place by Kubitect.
+  lifecycle {
+    ignore_changes = [size]
+  }
 }
 
 # Creates volume for new virtual machine #
@@ -69,6 +80,12 @@ resource "libvirt_domain" "vm_domain" {
     mode = var.vm_cpuMode
   }
 
+  # CPU and RAM of an existing VM are resized in place by Kubitect.
+  # Cloud-init disk is used only on the first boot of the VM.
+  lifecycle {
+    ignore_changes = [vcpu, memory, cloudinit]
+  }
+
   cloudinit = libvirt_cloudinit_disk.cloud_init.id
 
   qemu_agent = (var.network_mode == "bridge")
@@ -27,6 +27,9 @@ type AppContextOptions struct {
 	// Disable color in output
 	NoColor bool
 
+	// Output format
+	Output ui.OutputFormat
+
 	// Local deployment. Use working dir as project home dir.
 	Local bool
 
@@ -114,6 +117,7 @@ func (o AppContextOptions) AppContext() AppContext {
 		Debug:       o.Debug,
 		NoColor:     o.NoColor,
 		AutoApprove: o.AutoApprove,
+		Output:      o.Output,
 	}
 
 	// Initialize global ui
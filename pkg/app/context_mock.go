@@ -56,6 +56,7 @@ func MockAppContext(t *testing.T, opts ...AppContextOptions) AppContextMock {
 		AutoApprove: o.AutoApprove,
 		Debug:       o.Debug,
 		NoColor:     o.NoColor,
+		Output:      o.Output,
 	}
 
 	u := ui.MockGlobalTerminalUi(t, uOpts)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 
 	"github.com/MusicDin/kubitect/embed"
 	"github.com/MusicDin/kubitect/pkg/cluster/event"
@@ -19,9 +20,13 @@ type ApplyAction string
 
 const (
 	UNKNOWN ApplyAction = "unknown"
+	AUTO    ApplyAction = "auto"
 	CREATE  ApplyAction = "create"
 	UPGRADE ApplyAction = "upgrade"
 	SCALE   ApplyAction = "scale"
+
+	// SCALE_UPGRADE scales and upgrades the cluster within a single apply.
+	SCALE_UPGRADE ApplyAction = "scale-upgrade"
 )
 
 func (a ApplyAction) String() string {
@@ -37,6 +42,8 @@ func (a ApplyAction) rules() []event.Rule {
 		return event.ScaleRules
 	case UPGRADE:
 		return event.UpgradeRules
+	case SCALE_UPGRADE:
+		return event.ScaleUpgradeRules
 	default:
 		return nil
 	}
@@ -44,27 +51,63 @@ func (a ApplyAction) rules() []event.Rule {
 
 func ToApplyActionType(a string) (ApplyAction, error) {
 	switch a {
-	case CREATE.String(), "":
+	case AUTO.String(), "":
+		return AUTO, nil
+	case CREATE.String():
 		return CREATE, nil
 	case UPGRADE.String():
 		return UPGRADE, nil
 	case SCALE.String():
 		return SCALE, nil
+	case SCALE_UPGRADE.String():
+		return SCALE_UPGRADE, nil
 	default:
 		return UNKNOWN, fmt.Errorf("unknown cluster action: %s", a)
 	}
 }
 
 // Apply either creates new or modifies an existing cluster, based on the
-// provided action.
+// provided action. If action is omitted, it is detected from the changes
+// between the applied and the new configuration.
 func (c *Cluster) Apply(a string) error {
+	return c.applyAction(a, false)
+}
+
+// Resume works the same as Apply, except that phases completed by the
+// previous (failed) apply are skipped, as long as the previous apply has
+// been executed with the same action and configuration.
+func (c *Cluster) Resume(a string) error {
+	return c.applyAction(a, true)
+}
+
+func (c *Cluster) applyAction(a string, resume bool) error {
 	action, err := ToApplyActionType(a)
 
 	if err != nil {
 		return err
 	}
 
-	if c.AppliedConfig == nil && (action == SCALE || action == UPGRADE) {
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	// Applied configuration may have been changed by another process
+	// before the lock has been acquired.
+	if err := c.Sync(); err != nil {
+		return err
+	}
+
+	if action == AUTO {
+		action, err = c.detectAction()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.AppliedConfig == nil && (action == SCALE || action == UPGRADE || action == SCALE_UPGRADE) {
 		ui.Printf(ui.INFO, "Cannot %s cluster %q. It has not been created yet.\n\n", action, c.Name)
 
 		err := ui.Ask("Would you like to create it instead?")
@@ -82,62 +125,114 @@ func (c *Cluster) Apply(a string) error {
 
 	if c.AppliedConfig != nil && len(events) == 0 {
 		ui.Println(ui.INFO, "No changes detected.")
-		return nil
+		return c.printApplyResult(action, false)
+	}
+
+	if err := c.apply(action, events, resume); err != nil {
+		return err
 	}
 
+	return c.printApplyResult(action, true)
+}
+
+// apply prepares the cluster directory, executes the given action and
+// replaces the applied configuration with the new one. Progress of the
+// action is recorded in a checkpoint, which is removed once the new
+// configuration is applied. If resume is true, phases completed by the
+// previous apply are skipped. The new configuration is recorded in the
+// cluster history regardless of the outcome.
+func (c *Cluster) apply(action ApplyAction, events event.Events, resume bool) error {
 	if err := c.prepare(); err != nil {
 		return err
 	}
 
+	if err := c.startCheckpoint(action, resume); err != nil {
+		return err
+	}
+
+	var err error
+
 	switch action {
 	case CREATE:
-		err = c.create()
+		err = c.create(events)
 	case UPGRADE:
 		err = c.upgrade()
 	case SCALE:
 		err = c.scale(events)
+	case SCALE_UPGRADE:
+		err = c.scaleUpgrade(events)
 	}
 
 	if err != nil {
+		if rErr := c.recordRevision(action, err); rErr != nil {
+			ui.Printf(ui.WARN, "Failed to record the configuration history: %v\n", rErr)
+		}
+
+		return err
+	}
+
+	if err := c.ApplyNewConfig(); err != nil {
+		return err
+	}
+
+	if err := c.recordRevision(action, nil); err != nil {
 		return err
 	}
 
-	return c.ApplyNewConfig()
+	return c.clearCheckpoint()
+}
+
+// ApplyResult is a serializable result of the apply.
+type ApplyResult struct {
+	Cluster string      `json:"cluster" yaml:"cluster"`
+	Action  ApplyAction `json:"action" yaml:"action"`
+	Changed bool        `json:"changed" yaml:"changed"`
+}
+
+// printApplyResult prints the result of the apply when structured output
+// format is used.
+func (c *Cluster) printApplyResult(action ApplyAction, changed bool) error {
+	res := ApplyResult{
+		Cluster: c.Name,
+		Action:  action,
+		Changed: changed,
+	}
+
+	return ui.PrintObject("ApplyResult", res)
 }
 
 // plan compares an already applied configuration file with the new one, and
-// detects events based on the apply action. If cluster has not been
+// detects events based on the apply action. Detected errors and warnings are
+// printed and user is asked for confirmation. If cluster has not been
 // initialized yet, nil is returned both for an error and events.
 func (c *Cluster) plan(action ApplyAction) (event.Events, error) {
-	if c.AppliedConfig == nil {
-		return nil, nil
+	events, err := c.events(action)
+	if err != nil {
+		return nil, err
 	}
 
-	cmpOptions := cmp.Options{
-		Tag:                "opt",
-		ExtraNameTags:      []string{"yaml"},
-		RespectSliceOrder:  false,
-		IgnoreEmptyChanges: true,
-		PopulateAllNodes:   true,
+	if len(events) == 0 {
+		return nil, nil
 	}
 
-	// Compare configuration files.
-	res, err := cmp.Compare(c.AppliedConfig, c.NewConfig, cmpOptions)
-	if err != nil {
+	if err := ui.PrintObject("Events", events.Summaries()); err != nil {
 		return nil, err
 	}
 
-	// Return if there is no changes.
-	if !res.HasChanges() {
-		return nil, nil
+	if printErrorEvents(events) {
+		return nil, fmt.Errorf("Configuration file contains errors.")
 	}
 
-	// Generate events from detected configuration changes and provided rules.
-	events, err := event.GenerateEvents(res.Tree(), action.rules())
-	if err != nil {
-		return nil, err
+	if printWarnEvents(events) {
+		ui.Println(ui.INFO, "Above warnings indicate potentially dangerous actions.")
 	}
 
+	return events, ui.Ask()
+}
+
+// printErrorEvents prints events of type error and reports whether any
+// such event has been found.
+func printErrorEvents(events event.Events) bool {
 	hasError := false
 	for _, e := range events {
 		if !e.Rule.IsOfType(event.Error) {
@@ -145,6 +240,12 @@ func (c *Cluster) plan(action ApplyAction) (event.Events, error) {
 		}
 
 		hasError = true
+
+		// Events are printed as a whole in structured output.
+		if ui.Output().IsStructured() {
+			continue
+		}
+
 		if e.Change.Type == cmp.Create || e.Change.Type == cmp.Delete {
 			// For create and delete events, only change's
 			// path is shown.
@@ -156,10 +257,12 @@ func (c *Cluster) plan(action ApplyAction) (event.Events, error) {
 		}
 	}
 
-	if hasError {
-		return nil, fmt.Errorf("Configuration file contains errors.")
-	}
+	return hasError
+}
 
+// printWarnEvents prints events of type warning and reports whether any
+// such event has been found.
+func printWarnEvents(events event.Events) bool {
 	hasWarnings := false
 	for _, e := range events {
 		if !e.Rule.IsOfType(event.Warn) {
@@ -167,29 +270,120 @@ func (c *Cluster) plan(action ApplyAction) (event.Events, error) {
 		}
 
 		hasWarnings = true
+
+		// Events are printed as a whole in structured output.
+		if ui.Output().IsStructured() {
+			continue
+		}
+
 		err := NewConfigChangeWarning(e.Rule.Message, e.Change.Path)
 		ui.PrintBlockE(err)
 	}
 
-	if hasWarnings {
-		ui.Println(ui.INFO, "Above warnings indicate potentially dangerous actions.")
+	return hasWarnings
+}
+
+// events compares an already applied configuration file with the new one,
+// and generates events based on the rules of the given apply action and
+// the user-defined policy rules. If removal of control plane nodes would
+// break the etcd quorum, main disk of a node would shrink, or Kubernetes
+// version change is not a valid upgrade path, error events are appended.
+// If cluster has not been initialized yet or there are no changes, nil is
+// returned both for an error and events.
+func (c *Cluster) events(action ApplyAction) (event.Events, error) {
+	res, err := c.compare()
+	if err != nil || res == nil {
+		return nil, err
 	}
 
-	return events, ui.Ask()
+	rules, err := c.rules(action)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate events from detected configuration changes and provided rules.
+	events, err := event.GenerateEvents(res.Tree(), rules)
+	if err != nil {
+		return nil, err
+	}
+
+	events = append(events, c.quorumEvents(events)...)
+	events = append(events, resizeEvents(events)...)
+	events = append(events, c.upgradePathEvents(events)...)
+
+	return events, nil
 }
 
-// create creates a new cluster or modifies the current
-// one if the cluster already exists.
-func (c *Cluster) create() error {
-	if err := c.generateSshKeys(); err != nil {
-		return err
+// explain compares an already applied configuration file with the new one,
+// and explains how the rules of the given apply action and the user-defined
+// policy rules are matched with the detected changes. If cluster has not
+// been initialized yet or there are no changes, nil is returned both for an
+// error and explanations.
+func (c *Cluster) explain(action ApplyAction) ([]event.Explanation, error) {
+	res, err := c.compare()
+	if err != nil || res == nil {
+		return nil, err
 	}
 
-	if err := c.Provisioner().Init(nil); err != nil {
+	rules, err := c.rules(action)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.ExplainEvents(res.Tree(), rules)
+}
+
+// rules returns the rules of the given apply action followed by the
+// user-defined policy rules.
+func (c *Cluster) rules(action ApplyAction) ([]event.Rule, error) {
+	policyRules, err := c.policyRules(action)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(action.rules(), policyRules), nil
+}
+
+// configCmpOptions are options used to compare configuration files.
+var configCmpOptions = cmp.Options{
+	Tag:                "opt",
+	ExtraNameTags:      []string{"yaml"},
+	RespectSliceOrder:  false,
+	IgnoreEmptyChanges: true,
+	PopulateAllNodes:   true,
+}
+
+// compare compares an already applied configuration file with the new one.
+// If cluster has not been initialized yet or there are no changes, nil is
+// returned both for an error and the result.
+func (c *Cluster) compare() (*cmp.Result, error) {
+	if c.AppliedConfig == nil {
+		return nil, nil
+	}
+
+	// Compare configuration files.
+	res, err := cmp.Compare(c.AppliedConfig, c.NewConfig, configCmpOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return if there is no changes.
+	if !res.HasChanges() {
+		return nil, nil
+	}
+
+	return res, nil
+}
+
+// create creates a new cluster or modifies the current
+// one if the cluster already exists. Nodes with changed
+// resources are resized after the cluster is modified.
+func (c *Cluster) create(events event.Events) error {
+	if err := c.phase("ssh-keys", c.generateSshKeys); err != nil {
 		return err
 	}
 
-	if err := c.Provisioner().Apply(); err != nil {
+	if err := c.provision(nil); err != nil {
 		return err
 	}
 
@@ -205,16 +399,16 @@ func (c *Cluster) create() error {
 		return err
 	}
 
-	return c.Manager().Create()
+	if err := c.Manager().Create(); err != nil {
+		return err
+	}
+
+	return c.resize(events)
 }
 
 // upgrade upgrades an existing cluster.
 func (c *Cluster) upgrade() error {
-	if err := c.Provisioner().Init(nil); err != nil {
-		return err
-	}
-
-	if err := c.Provisioner().Apply(); err != nil {
+	if err := c.provision(nil); err != nil {
 		return err
 	}
 
@@ -243,11 +437,7 @@ func (c *Cluster) scale(events []event.Event) error {
 		return err
 	}
 
-	if err := c.Provisioner().Init(events); err != nil {
-		return err
-	}
-
-	if err := c.Provisioner().Apply(); err != nil {
+	if err := c.provision(events); err != nil {
 		return err
 	}
 
@@ -262,6 +452,20 @@ func (c *Cluster) scale(events []event.Event) error {
 	return c.Manager().ScaleUp(events)
 }
 
+// provision initializes the provisioner and applies the infrastructure
+// changes.
+func (c *Cluster) provision(events []event.Event) error {
+	err := c.phase("provisioner-init", func() error {
+		return c.Provisioner().Init(events)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.phase("provisioner-apply", c.Provisioner().Apply)
+}
+
 // prepare prepares the cluster directory. It ensures all required project
 // files are present in the directory and new configuration file is stored in
 // the temporary location.
@@ -300,9 +504,13 @@ func (c *Cluster) prepare() error {
 func (c *Cluster) generateSshKeys() error {
 	ui.Println(ui.INFO, "Ensuring SSH keys are present...")
 
-	kpName := path.Base(c.PrivateSshKeyPath())
-	kpDir := path.Dir(c.PrivateSshKeyPath())
+	return c.writeSshKeys(path.Dir(c.PrivateSshKeyPath()), path.Base(c.PrivateSshKeyPath()))
+}
 
+// writeSshKeys ensures that the SSH key pair with the given name exists
+// in the given directory. Missing key pair is either copied from the path
+// set in the node template or generated.
+func (c *Cluster) writeSshKeys(kpDir string, kpName string) error {
 	// Stop if key pair already exists
 	if keygen.KeyPairExists(kpDir, kpName) {
 		return nil
@@ -321,14 +529,13 @@ func (c *Cluster) generateSshKeys() error {
 	}
 
 	// Keypair does not exist and user has not provided a custom path.
-	// - Generate new key pair
-	kp, err := keygen.NewKeyPair(4096)
+	// - Generate new key pair of the configured type
+	kp, err := newSshKeyPair(c.NewConfig.Cluster.NodeTemplate.SSH)
 	if err != nil {
 		return err
 	}
 
 	return kp.Write(kpDir, kpName)
-
 }
 
 // verifyClusterDir verifies if the provided cluster directory
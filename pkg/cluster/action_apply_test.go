@@ -2,13 +2,16 @@ package cluster
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/MusicDin/kubitect/pkg/env"
 	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestToApplyAction(t *testing.T) {
@@ -21,6 +24,9 @@ func TestToApplyAction(t *testing.T) {
 
 	a, _ = ToApplyActionType(string(SCALE))
 	assert.Equal(t, SCALE, a)
+
+	a, _ = ToApplyActionType("")
+	assert.Equal(t, AUTO, a)
 }
 
 func TestToApplyAction_Invalid(t *testing.T) {
@@ -129,7 +135,7 @@ func TestApply_Upgrade(t *testing.T) {
 	assert.NoError(t, c.Sync())
 
 	// Make a valid configuration change.
-	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.26.5")
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.28.10")
 
 	// Skip required files check.
 	tmp := env.ProjectRequiredFiles
@@ -158,3 +164,29 @@ func TestApply_Scale(t *testing.T) {
 
 	assert.NoError(t, c.Apply(SCALE.String()))
 }
+
+func TestApply_Structured(t *testing.T) {
+	c := MockStructuredCluster(t, ui.OutputJson)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	// Append worker node
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	assert.NoError(t, c.Apply(SCALE.String()))
+
+	out := strings.Split(strings.TrimSpace(c.Ui().ReadStdout(t)), "\n")
+	require.Len(t, out, 2)
+	assert.Contains(t, out[0], `"kind":"Events"`)
+	assert.Contains(t, out[0], `"path":"cluster.nodes.worker.instances.worker"`)
+	assert.JSONEq(t, `{"kind":"ApplyResult","data":{"cluster":"cluster-mock","action":"scale","changed":true}}`, out[1])
+}
@@ -8,13 +8,27 @@ import (
 	"github.com/MusicDin/kubitect/pkg/utils/file"
 )
 
+// DestroyResult is a serializable result of the destroy.
+type DestroyResult struct {
+	Cluster   string `json:"cluster" yaml:"cluster"`
+	Destroyed bool   `json:"destroyed" yaml:"destroyed"`
+}
+
 // Destroy destroys the cluster and removes cluster's directory.
 // Terraform resources are wiped if terraform state file is found.
+// The cluster is locked for the duration of the destroy.
 func (c *ClusterMeta) Destroy() error {
 	if !file.Exists(c.Path) {
 		return fmt.Errorf("cluster %q does not exist", c.Name)
 	}
 
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
 	ui.Printf(ui.INFO, "Cluster %q will be destroyed.\n", c.Name)
 	if err := ui.Ask(); err != nil {
 		return err
@@ -36,5 +50,8 @@ func (c *ClusterMeta) Destroy() error {
 	}
 
 	ui.Printf(ui.INFO, "Cluster %q has been successfully destroyed.\n", c.Name)
-	return nil
+	return ui.PrintObject("DestroyResult", DestroyResult{
+		Cluster:   c.Name,
+		Destroyed: true,
+	})
 }
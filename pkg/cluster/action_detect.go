@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
+
+// detectableActions are apply actions that can be detected automatically,
+// ordered by their priority. If multiple actions allow all detected
+// changes, the first one is selected.
+var detectableActions = []ApplyAction{
+	CREATE,
+	SCALE,
+	UPGRADE,
+	SCALE_UPGRADE,
+}
+
+// detectAction compares an already applied configuration file with the new
+// one, and returns the apply action whose rules, including the policy
+// rules, allow all detected changes.
+// If cluster has not been created yet or there are no changes, the create
+// action is returned. If changes require different actions, an error is
+// returned describing which changes require which action.
+func (c *Cluster) detectAction() (ApplyAction, error) {
+	res, err := c.compare()
+	if err != nil {
+		return UNKNOWN, err
+	}
+
+	if res == nil {
+		return CREATE, nil
+	}
+
+	// Events are generated the same way as when the action is applied,
+	// so that policy rules and additional checks are taken into account.
+	actionEvents := make(map[ApplyAction]event.Events)
+	for _, a := range detectableActions {
+		events, err := c.events(a)
+		if err != nil {
+			return UNKNOWN, err
+		}
+
+		actionEvents[a] = events
+	}
+
+	for _, a := range detectableActions {
+		if len(actionEvents[a].FilterByRuleType(event.Error)) == 0 {
+			ui.Printf(ui.INFO, "Detected action: %s\n", a)
+			return a, nil
+		}
+	}
+
+	ui.PrintBlockE(newMixedChangesError(actionEvents))
+	return UNKNOWN, fmt.Errorf("Configuration file contains changes that cannot be applied with a single action.")
+}
+
+// newMixedChangesError returns an error block that groups disallowed
+// changes by the actions that allow them.
+func newMixedChangesError(actionEvents map[ApplyAction]event.Events) error {
+	var groups [][]ApplyAction
+	paths := make(map[string][]string)
+
+	for _, a := range detectableActions {
+		for _, e := range actionEvents[a].FilterByRuleType(event.Error) {
+			for _, p := range e.MatchedChangePaths {
+				allowedBy := allowingActions(actionEvents, p)
+
+				key := fmt.Sprint(allowedBy)
+				if _, ok := paths[key]; !ok {
+					groups = append(groups, allowedBy)
+				}
+
+				// Changes not allowed by any action are reported
+				// as seen by any action.
+				candidates := allowedBy
+				if len(candidates) == 0 {
+					candidates = detectableActions
+				}
+
+				cp := changePath(actionEvents, candidates, p)
+				if !slices.Contains(paths[key], cp) {
+					paths[key] = append(paths[key], cp)
+				}
+			}
+		}
+	}
+
+	content := []ui.Content{
+		ui.NewErrorLine("Error type:", "Mixed Configuration Changes"),
+	}
+
+	for _, g := range groups {
+		ps := paths[fmt.Sprint(g)]
+
+		if len(g) == 0 {
+			content = append(content, ui.NewErrorSection("Not allowed by any action:", ps...).WithKey("notAllowed"))
+			continue
+		}
+
+		var names []string
+		for _, a := range g {
+			names = append(names, fmt.Sprintf("'--action %s'", a))
+		}
+
+		title := fmt.Sprintf("Requires %s:", strings.Join(names, " or "))
+		content = append(content, ui.NewErrorSection(title, ps...).WithKey("requires"+actionsKey(g)))
+	}
+
+	msg := "Detected changes require different apply actions. Apply them separately, each with the corresponding action."
+	content = append(content, ui.NewErrorSection("Error:", msg))
+
+	return ui.NewErrorBlock(ui.ERROR, content)
+}
+
+// allowingActions returns actions that do not produce an error event for
+// a change on the given path.
+func allowingActions(actionEvents map[ApplyAction]event.Events, path string) []ApplyAction {
+	var actions []ApplyAction
+
+	for _, a := range detectableActions {
+		allowed := true
+		for _, e := range actionEvents[a].FilterByRuleType(event.Error) {
+			if slices.Contains(e.MatchedChangePaths, path) {
+				allowed = false
+				break
+			}
+		}
+
+		if allowed {
+			actions = append(actions, a)
+		}
+	}
+
+	return actions
+}
+
+// changePath returns the path of the change as reported by the event of
+// the first allowing action. For example, for added node, path of the node
+// is returned instead of the path of each of its properties.
+func changePath(actionEvents map[ApplyAction]event.Events, actions []ApplyAction, path string) string {
+	for _, a := range actions {
+		for _, e := range actionEvents[a] {
+			if slices.Contains(e.MatchedChangePaths, path) {
+				return e.Change.Path
+			}
+		}
+	}
+
+	return path
+}
+
+// actionsKey converts actions into a key of a structured output, for
+// example "CreateOrScale".
+func actionsKey(actions []ApplyAction) string {
+	var names []string
+	for _, a := range actions {
+		s := a.String()
+		names = append(names, strings.ToUpper(s[:1])+s[1:])
+	}
+
+	return strings.Join(names, "Or")
+}
@@ -0,0 +1,201 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAction_NewCluster(t *testing.T) {
+	c := MockCluster(t)
+
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, CREATE, a)
+}
+
+func TestDetectAction_NoChanges(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, CREATE, a)
+}
+
+func TestDetectAction_Scale(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, SCALE, a)
+	assert.Contains(t, c.Ui().ReadStdout(t), "Detected action: scale")
+}
+
+func TestDetectAction_LegacyAppliedConfig(t *testing.T) {
+	c := MockCluster(t)
+
+	require.NoError(t, c.ApplyNewConfig())
+
+	// Remove the upgrade properties from the applied configuration, as
+	// they are missing in configurations applied by older versions.
+	applied, err := file.ReadYaml(c.AppliedConfigPath(), map[string]any{})
+	require.NoError(t, err)
+	delete((*applied)["kubernetes"].(map[string]any), "upgrade")
+	require.NoError(t, file.WriteYaml(applied, c.AppliedConfigPath(), 0644))
+
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, SCALE, a)
+}
+
+func TestDetectAction_Upgrade(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.28.10")
+
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, UPGRADE, a)
+}
+
+func TestDetectAction_Downgrade(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.26.5")
+
+	_, err := c.detectAction()
+	assert.EqualError(t, err, "Configuration file contains changes that cannot be applied with a single action.")
+	assert.Contains(t, c.Ui().ReadStderr(t), "kubernetes.version")
+}
+
+func TestDetectAction_MixedChanges(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Addons.Rook.Enabled = true
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	_, err := c.detectAction()
+	assert.EqualError(t, err, "Configuration file contains changes that cannot be applied with a single action.")
+
+	out := c.Ui().ReadStderr(t)
+	assert.Contains(t, out, "Requires '--action scale' or")
+	assert.Contains(t, out, "cluster.nodes.worker.instances.worker")
+	assert.Contains(t, out, "Requires '--action create':")
+	assert.Contains(t, out, "addons.rook.enabled")
+}
+
+func TestDetectAction_ScaleUpgrade(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.28.10")
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, SCALE_UPGRADE, a)
+}
+
+func TestDetectAction_Policy(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: cluster.nodes.worker.instances.*
+    type: error
+    actions: [scale]
+`)
+
+	// Scale action is skipped, since the policy does not allow it.
+	a, err := c.detectAction()
+	require.NoError(t, err)
+	assert.Equal(t, SCALE_UPGRADE, a)
+}
+
+func TestDetectAction_Quorum(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 3)
+
+	c.NewConfig.Cluster.Nodes.Master.Instances = c.NewConfig.Cluster.Nodes.Master.Instances[:1]
+
+	_, err := c.detectAction()
+	assert.EqualError(t, err, "Configuration file contains changes that cannot be applied with a single action.")
+	assert.Contains(t, c.Ui().ReadStderr(t), "Not allowed by any action:")
+}
+
+func TestApply_DetectAction(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	assert.NoError(t, c.Apply(""))
+	assert.Contains(t, c.Ui().ReadStdout(t), "Detected action: scale")
+}
+
+func TestActionsKey(t *testing.T) {
+	assert.Equal(t, "Scale", actionsKey([]ApplyAction{SCALE}))
+	assert.Equal(t, "CreateOrScale", actionsKey([]ApplyAction{CREATE, SCALE}))
+}
@@ -0,0 +1,524 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/MusicDin/kubitect/embed"
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/provisioner"
+	"github.com/MusicDin/kubitect/pkg/cluster/provisioner/terraform"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+)
+
+// Plan contains changes that would be made to the cluster if the new
+// configuration was applied with a specific action.
+type Plan struct {
+	Action ApplyAction
+	Events event.Events
+
+	// Explanations describe how rules have been matched with the detected
+	// changes. They are set only if the explanation is enabled.
+	Explanations []event.Explanation
+
+	// NewCluster indicates that the cluster has not been created yet.
+	NewCluster bool
+
+	// InfraChanges indicates that the provisioner has detected changes
+	// of the cluster infrastructure.
+	InfraChanges bool
+
+	// Configuration files the plan has been created from.
+	ClusterName       string
+	NewConfigPath     string
+	NewConfigHash     string
+	AppliedConfigHash string
+	PolicyPath        string
+	PolicyHash        string
+}
+
+// HasChanges returns true if applying the plan would modify the cluster.
+func (p Plan) HasChanges() bool {
+	if p.NewCluster || p.InfraChanges {
+		return true
+	}
+
+	for _, e := range p.Events {
+		if !e.Rule.IsOfType(event.Ignore) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasErrors returns true if the plan contains any change that is not
+// allowed by the apply action.
+func (p Plan) HasErrors() bool {
+	for _, e := range p.Events {
+		if e.Rule.IsOfType(event.Error) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newPlanProvisioner returns a provisioner that plans infrastructure
+// changes of the cluster within the given cluster directory.
+var newPlanProvisioner = func(c *Cluster, clusterPath string) provisioner.Provisioner {
+	return terraform.NewTerraformProvisioner(
+		clusterPath,
+		c.ShareDir(),
+		c.ShowTerraformPlan(),
+		c.NewConfig,
+	)
+}
+
+// Plan previews the changes that would be made by applying the new
+// configuration with the given action. Neither virtual machines nor
+// Kubernetes cluster are modified in the process, and no files are
+// written into the cluster directory. The cluster lock is held for the
+// whole plan, so that the cluster is not modified while it is planned.
+func (c *Cluster) Plan(a string) (*Plan, error) {
+	action, err := ToApplyActionType(a)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	// Applied configuration may have been changed by another process
+	// before the lock has been acquired.
+	if err := c.Sync(); err != nil {
+		return nil, err
+	}
+
+	if action == AUTO {
+		action, err = c.detectAction()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p := &Plan{
+		Action:      action,
+		ClusterName: c.Name,
+	}
+
+	p.NewConfigPath, err = filepath.Abs(c.NewConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p.NewConfigHash, err = fileHash(c.NewConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p.AppliedConfigHash, err = fileHash(c.AppliedConfigPath())
+	if err != nil {
+		return nil, err
+	}
+
+	if c.PolicyPath != "" {
+		p.PolicyPath, err = filepath.Abs(c.PolicyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		p.PolicyHash, err = fileHash(c.PolicyPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.AppliedConfig == nil {
+		ui.Printf(ui.INFO, "Cluster %q has not been created yet. Applying the configuration will create it.\n", c.Name)
+		p.Action = CREATE
+		p.NewCluster = true
+	} else {
+		p.Events, err = c.events(action)
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.HasChanges() {
+			ui.Println(ui.INFO, "No changes detected.")
+			return p, nil
+		}
+
+		printErrorEvents(p.Events)
+		printWarnEvents(p.Events)
+		printPlanEvents(p)
+
+		if c.Explain {
+			p.Explanations, err = c.explain(action)
+			if err != nil {
+				return p, err
+			}
+
+			printExplanations(p.Explanations)
+		}
+
+		if p.HasErrors() {
+			return p, fmt.Errorf("Configuration file contains errors.")
+		}
+	}
+
+	p.InfraChanges, err = c.planInfrastructure(p.Events)
+	if err != nil {
+		return p, err
+	}
+
+	if p.InfraChanges {
+		ui.Println(ui.INFO, "Infrastructure changes have been detected.")
+	} else {
+		ui.Println(ui.INFO, "No infrastructure changes have been detected.")
+	}
+
+	return p, nil
+}
+
+// planInfrastructure returns true if the provisioner detects changes of
+// the cluster infrastructure. Infrastructure is planned within a
+// temporary copy of the cluster directory, since the provisioner
+// regenerates its files before the plan.
+func (c *Cluster) planInfrastructure(events event.Events) (bool, error) {
+	dir, err := os.MkdirTemp("", "kubitect-plan-")
+	if err != nil {
+		return false, fmt.Errorf("create plan directory: %v", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err := embed.MirrorResource("terraform", dir); err != nil {
+		return false, err
+	}
+
+	// Terraform project refers to the following files relatively to the
+	// cluster directory.
+	files := []string{
+		c.TfStatePath(),
+		c.InfrastructureConfigPath(),
+		c.PrivateSshKeyPath(),
+		c.PrivateSshKeyPath() + ".pub",
+	}
+
+	for _, f := range files {
+		if !file.Exists(f) {
+			continue
+		}
+
+		rel, err := filepath.Rel(c.Path, f)
+		if err != nil {
+			return false, err
+		}
+
+		if err := file.Copy(f, filepath.Join(dir, rel), 0600); err != nil {
+			return false, err
+		}
+	}
+
+	// SSH keys of a new cluster do not exist yet, but the public key is
+	// read by Terraform. Keys are prepared the same way as on apply.
+	kpDir := filepath.Join(dir, DefaultConfigDir, ".ssh")
+	kpName := filepath.Base(c.PrivateSshKeyPath())
+
+	if err := c.writeSshKeys(kpDir, kpName); err != nil {
+		return false, err
+	}
+
+	prov := newPlanProvisioner(c, dir)
+
+	if err := prov.Init(events); err != nil {
+		return false, err
+	}
+
+	return prov.Plan()
+}
+
+// PlanSummary is a serializable representation of the plan.
+type PlanSummary struct {
+	Cluster      string          `json:"cluster" yaml:"cluster"`
+	Action       ApplyAction     `json:"action" yaml:"action"`
+	NewCluster   bool            `json:"newCluster" yaml:"newCluster"`
+	InfraChanges bool            `json:"infraChanges" yaml:"infraChanges"`
+	HasChanges   bool            `json:"hasChanges" yaml:"hasChanges"`
+	HasErrors    bool            `json:"hasErrors" yaml:"hasErrors"`
+	Events       []event.Summary `json:"events" yaml:"events"`
+
+	Explanations []event.ExplanationSummary `json:"explanations,omitempty" yaml:"explanations,omitempty"`
+}
+
+// Summary returns a serializable representation of the plan.
+func (p Plan) Summary() PlanSummary {
+	return PlanSummary{
+		Cluster:      p.ClusterName,
+		Action:       p.Action,
+		NewCluster:   p.NewCluster,
+		InfraChanges: p.InfraChanges,
+		HasChanges:   p.HasChanges(),
+		HasErrors:    p.HasErrors(),
+		Events:       p.Events.Summaries(),
+		Explanations: explanationSummaries(p.Explanations),
+	}
+}
+
+// explanationSummaries returns serializable representations of the
+// explanations.
+func explanationSummaries(expls []event.Explanation) []event.ExplanationSummary {
+	var summaries []event.ExplanationSummary
+	for _, e := range expls {
+		summaries = append(summaries, e.Summary())
+	}
+
+	return summaries
+}
+
+// printPlanEvents prints plan events grouped by their rule and action type.
+func printPlanEvents(p *Plan) {
+	groups := []struct {
+		title  string
+		events event.Events
+	}{
+		{
+			title: "Scale down",
+			events: p.Events.Filter(func(e event.Event) bool {
+				return e.Rule.IsOfType(event.Allow) && e.Rule.ActionType == event.Action_ScaleDown
+			}),
+		},
+		{
+			title: "Scale up",
+			events: p.Events.Filter(func(e event.Event) bool {
+				return e.Rule.IsOfType(event.Allow) && e.Rule.ActionType == event.Action_ScaleUp
+			}),
+		},
+		{
+			title: "Allowed",
+			events: p.Events.Filter(func(e event.Event) bool {
+				return e.Rule.IsOfType(event.Allow) && e.Rule.ActionType == ""
+			}),
+		},
+		{
+			title:  "Warnings",
+			events: p.Events.Filter(func(e event.Event) bool { return e.Rule.IsOfType(event.Warn) }),
+		},
+		{
+			title:  "Errors",
+			events: p.Events.Filter(func(e event.Event) bool { return e.Rule.IsOfType(event.Error) }),
+		},
+	}
+
+	ui.Printf(ui.INFO, "Detected changes (action: %s):\n", p.Action)
+
+	for _, g := range groups {
+		if len(g.events) == 0 {
+			continue
+		}
+
+		ui.Printf(ui.INFO, "  %s:\n", g.title)
+		for _, e := range g.events {
+			ui.Printf(ui.INFO, "    - %s\n", e.Change)
+		}
+	}
+}
+
+// printExplanations prints each detected change along with the rules it
+// matches. The selected rule is marked with '>', and for each rule the
+// reason of its selection or rejection is shown.
+func printExplanations(expls []event.Explanation) {
+	ui.Println(ui.INFO, "Rule matching:")
+
+	for _, e := range expls {
+		ui.Printf(ui.INFO, "  %s\n", e.Change)
+
+		if e.Rule == nil {
+			ui.Println(ui.INFO, "      No rule matches the change, therefore it is ignored.")
+			continue
+		}
+
+		for _, c := range e.Summary().Candidates {
+			mark := " "
+			if c.Selected {
+				mark = ">"
+			}
+
+			kind := "built-in"
+			if c.Policy {
+				kind = "policy"
+			}
+
+			ui.Printf(ui.INFO, "    %s %-5s %s (%s, %s): %s\n", mark, c.RuleType, c.RulePath, c.ChangeType, kind, c.Reason)
+		}
+
+		if e.Rule.ActionType != "" {
+			ui.Printf(ui.INFO, "      Action type: %s\n", e.Rule.ActionType)
+		}
+	}
+}
+
+// PlanFile is a serializable representation of the plan that can be stored
+// and later applied exactly as it has been planned.
+type PlanFile struct {
+	KubitectVersion   string          `json:"kubitectVersion" yaml:"kubitectVersion"`
+	ClusterName       string          `json:"clusterName" yaml:"clusterName"`
+	Action            ApplyAction     `json:"action" yaml:"action"`
+	ConfigPath        string          `json:"configPath" yaml:"configPath"`
+	ConfigHash        string          `json:"configHash" yaml:"configHash"`
+	AppliedConfigHash string          `json:"appliedConfigHash,omitempty" yaml:"appliedConfigHash,omitempty"`
+	PolicyPath        string          `json:"policyPath,omitempty" yaml:"policyPath,omitempty"`
+	PolicyHash        string          `json:"policyHash,omitempty" yaml:"policyHash,omitempty"`
+	Events            []event.Summary `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+// File returns a serializable representation of the plan.
+func (p Plan) File() PlanFile {
+	return PlanFile{
+		KubitectVersion:   env.ConstProjectVersion,
+		ClusterName:       p.ClusterName,
+		Action:            p.Action,
+		ConfigPath:        p.NewConfigPath,
+		ConfigHash:        p.NewConfigHash,
+		AppliedConfigHash: p.AppliedConfigHash,
+		PolicyPath:        p.PolicyPath,
+		PolicyHash:        p.PolicyHash,
+		Events:            p.Events.Summaries(),
+	}
+}
+
+// Write writes the plan file to the given path.
+func (pf PlanFile) Write(path string) error {
+	err := file.WriteYaml(pf, path, 0600)
+	if err != nil {
+		return fmt.Errorf("write plan file: %v", err)
+	}
+
+	return nil
+}
+
+// ReadPlanFile reads the plan file on the given path.
+func ReadPlanFile(path string) (*PlanFile, error) {
+	if !file.Exists(path) {
+		return nil, fmt.Errorf("plan file %q does not exist", path)
+	}
+
+	pf, err := file.ReadYamlStrict(path, PlanFile{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan file %q: %v", path, err)
+	}
+
+	return pf, nil
+}
+
+// ApplyPlan applies the new configuration exactly as recorded in the given
+// plan file. It fails if either applied configuration, new configuration
+// or policy file has changed since the plan has been created.
+func (c *Cluster) ApplyPlan(pf PlanFile) error {
+	if pf.KubitectVersion != env.ConstProjectVersion {
+		return fmt.Errorf("plan has been created with Kubitect %s, but current version is %s", pf.KubitectVersion, env.ConstProjectVersion)
+	}
+
+	if pf.ClusterName != c.Name {
+		return fmt.Errorf("plan has been created for cluster %q, but configuration file refers to cluster %q", pf.ClusterName, c.Name)
+	}
+
+	action, err := ToApplyActionType(pf.Action.String())
+	if err != nil {
+		return err
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if err := c.Sync(); err != nil {
+		return err
+	}
+
+	appliedHash, err := fileHash(c.AppliedConfigPath())
+	if err != nil {
+		return err
+	}
+
+	if appliedHash != pf.AppliedConfigHash {
+		return fmt.Errorf("applied configuration of cluster %q has changed since the plan has been created", c.Name)
+	}
+
+	newHash, err := fileHash(c.NewConfigPath)
+	if err != nil {
+		return err
+	}
+
+	if newHash != pf.ConfigHash {
+		return fmt.Errorf("configuration file %q has changed since the plan has been created", c.NewConfigPath)
+	}
+
+	policyHash, err := fileHash(c.PolicyPath)
+	if err != nil {
+		return err
+	}
+
+	if policyHash != pf.PolicyHash {
+		return fmt.Errorf("policy file %q has changed since the plan has been created", c.PolicyPath)
+	}
+
+	events, err := c.events(action)
+	if err != nil {
+		return err
+	}
+
+	if !equalSummaries(events.Summaries(), pf.Events) {
+		return fmt.Errorf("detected changes differ from the ones recorded in the plan")
+	}
+
+	if printErrorEvents(events) {
+		return fmt.Errorf("Configuration file contains errors.")
+	}
+
+	if c.AppliedConfig != nil && len(events) == 0 {
+		ui.Println(ui.INFO, "No changes detected.")
+		return c.printApplyResult(action, false)
+	}
+
+	ui.Printf(ui.INFO, "Applying saved plan (action: %s)...\n", action)
+	if err := c.apply(action, events, false); err != nil {
+		return err
+	}
+
+	return c.printApplyResult(action, true)
+}
+
+// equalSummaries returns true if both lists contain equal event summaries
+// in the same order. Empty and nil lists are considered equal, since empty
+// lists are omitted from the plan file.
+func equalSummaries(a, b []event.Summary) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].RuleType != b[i].RuleType ||
+			a[i].ActionType != b[i].ActionType ||
+			a[i].ChangeType != b[i].ChangeType ||
+			a[i].Path != b[i].Path ||
+			a[i].Message != b[i].Message ||
+			!slices.Equal(a[i].MatchedChangePaths, b[i].MatchedChangePaths) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,253 @@
+package cluster
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/provisioner"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_NewCluster(t *testing.T) {
+	c := MockCluster(t)
+
+	p, err := c.Plan(SCALE.String())
+	require.NoError(t, err)
+	assert.True(t, p.NewCluster)
+	assert.True(t, p.HasChanges())
+	assert.Equal(t, CREATE, p.Action)
+	assert.True(t, p.InfraChanges)
+	assert.NoDirExists(t, c.Path)
+}
+
+func TestPlan_NoChanges(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	p, err := c.Plan(CREATE.String())
+	require.NoError(t, err)
+	assert.False(t, p.HasChanges())
+	assert.Contains(t, c.Ui().ReadStdout(t), "No changes detected.")
+}
+
+func TestPlan_Scale(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	p, err := c.Plan(SCALE.String())
+	require.NoError(t, err)
+	assert.True(t, p.HasChanges())
+	assert.False(t, p.HasErrors())
+	assert.True(t, p.InfraChanges)
+
+	// Plan does not write into the cluster directory.
+	assert.NoDirExists(t, path.Join(c.Path, "terraform"))
+	assert.NoFileExists(t, path.Join(c.ConfigDir(), DefaultNewConfigFilename))
+
+	out := c.Ui().ReadStdout(t)
+	assert.Contains(t, out, "Scale up:")
+	assert.Contains(t, out, "cluster.nodes.worker.instances.worker")
+}
+
+// planDirProvisioner is a provisioner that records the directory in which
+// the infrastructure is planned, along with the files present on init.
+type planDirProvisioner struct {
+	provisioner.Provisioner
+
+	dir   string
+	files *[]string
+}
+
+func (p planDirProvisioner) Init(events []event.Event) error {
+	return filepath.WalkDir(p.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(p.dir, path)
+		*p.files = append(*p.files, rel)
+		return err
+	})
+}
+
+func TestPlan_InfrastructureDir(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	require.NoError(t, os.MkdirAll(path.Dir(c.TfStatePath()), os.ModePerm))
+	require.NoError(t, os.WriteFile(c.TfStatePath(), []byte("{}"), 0600))
+
+	var dir string
+	var files []string
+
+	tmp := newPlanProvisioner
+	newPlanProvisioner = func(c *Cluster, clusterPath string) provisioner.Provisioner {
+		dir = clusterPath
+		return planDirProvisioner{c.Provisioner(), clusterPath, &files}
+	}
+
+	defer func() { newPlanProvisioner = tmp }()
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	_, err := c.Plan(SCALE.String())
+	require.NoError(t, err)
+
+	assert.NotEqual(t, c.Path, dir)
+	assert.NoDirExists(t, dir)
+	assert.Contains(t, files, filepath.Join(DefaultTerraformDir, DefaultTerraformStateFilename))
+	assert.Contains(t, files, filepath.Join(DefaultConfigDir, ".ssh", "id_rsa.pub"))
+	assert.Contains(t, files, filepath.Join("terraform", "versions.tf"))
+	assert.NoDirExists(t, path.Join(c.Path, "terraform"))
+}
+
+func TestPlan_InvalidChange(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.26.5")
+
+	p, err := c.Plan(SCALE.String())
+	assert.EqualError(t, err, "Configuration file contains errors.")
+	assert.True(t, p.HasErrors())
+}
+
+func TestPlan_Explain(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.26.5")
+	c.Explain = true
+
+	p, err := c.Plan(SCALE.String())
+	assert.EqualError(t, err, "Configuration file contains errors.")
+	require.Len(t, p.Explanations, 1)
+	assert.Equal(t, "kubernetes.version", p.Explanations[0].Change.Path)
+	assert.Len(t, p.Summary().Explanations, 1)
+
+	out := c.Ui().ReadStdout(t)
+	assert.Contains(t, out, "Rule matching:")
+	assert.Contains(t, out, "> Error * (any, built-in): best match")
+}
+
+func TestPlan_InvalidAction(t *testing.T) {
+	c := MockCluster(t)
+
+	_, err := c.Plan("invalid")
+	assert.EqualError(t, err, "unknown cluster action: invalid")
+}
+
+func TestPlanFile_Apply(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	p, err := c.Plan(CREATE.String())
+	require.NoError(t, err)
+
+	pfPath := path.Join(t.TempDir(), "plan.yaml")
+	require.NoError(t, p.File().Write(pfPath))
+
+	pf, err := ReadPlanFile(pfPath)
+	require.NoError(t, err)
+	assert.Equal(t, p.Action, pf.Action)
+	assert.Equal(t, p.NewConfigHash, pf.ConfigHash)
+	assert.Equal(t, p.AppliedConfigHash, pf.AppliedConfigHash)
+	assert.NoError(t, c.ApplyPlan(*pf))
+}
+
+func TestPlanFile_ConfigChanged(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	p, err := c.Plan(CREATE.String())
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(c.NewConfigPath, []byte("modified"), 0600))
+
+	err = c.ApplyPlan(p.File())
+	assert.ErrorContains(t, err, "has changed since the plan has been created")
+}
+
+func TestPlanFile_PolicyChanged(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: cluster.nodes.*.instances.*.ram
+    type: error
+`)
+
+	p, err := c.Plan(CREATE.String())
+	require.NoError(t, err)
+
+	pf := p.File()
+	assert.NotEmpty(t, pf.PolicyHash)
+
+	require.NoError(t, os.WriteFile(c.PolicyPath, []byte("rules: []"), 0600))
+
+	err = c.ApplyPlan(pf)
+	assert.EqualError(t, err, fmt.Sprintf("policy file %q has changed since the plan has been created", c.PolicyPath))
+}
+
+func TestPlanFile_AppliedConfigChanged(t *testing.T) {
+	c := MockCluster(t)
+
+	p, err := c.Plan(CREATE.String())
+	require.NoError(t, err)
+
+	assert.NoError(t, c.ApplyNewConfig())
+
+	err = c.ApplyPlan(p.File())
+	assert.EqualError(t, err, fmt.Sprintf("applied configuration of cluster %q has changed since the plan has been created", c.Name))
+}
+
+func TestPlanFile_InvalidVersion(t *testing.T) {
+	c := MockCluster(t)
+
+	p, err := c.Plan(CREATE.String())
+	require.NoError(t, err)
+
+	pf := p.File()
+	pf.KubitectVersion = "v0.0.0"
+
+	err = c.ApplyPlan(pf)
+	assert.ErrorContains(t, err, "plan has been created with Kubitect v0.0.0")
+}
+
+func TestReadPlanFile_NotExists(t *testing.T) {
+	_, err := ReadPlanFile("invalid.yaml")
+	assert.EqualError(t, err, `plan file "invalid.yaml" does not exist`)
+}
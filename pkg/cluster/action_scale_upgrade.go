@@ -0,0 +1,112 @@
+package cluster
+
+import (
+	"slices"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
+
+// scaleUpgrade scales and upgrades an existing cluster in the following
+// order:
+//  1. Removed nodes are removed from the cluster and destroyed.
+//  2. Existing nodes are upgraded to the new Kubernetes version.
+//  3. New nodes are provisioned and joined to the cluster with the new
+//     Kubernetes version.
+func (c *Cluster) scaleUpgrade(events event.Events) error {
+	ui.Println(ui.INFO, "Scaling down the cluster (phase 1/3)...")
+
+	if err := c.scaleDownPhase(events); err != nil {
+		return err
+	}
+
+	ui.Println(ui.INFO, "Upgrading existing nodes (phase 2/3)...")
+
+	if err := c.upgradePhase(events); err != nil {
+		return err
+	}
+
+	ui.Println(ui.INFO, "Scaling up the cluster (phase 3/3)...")
+
+	if err := c.provision(events); err != nil {
+		return err
+	}
+
+	if err := c.Sync(); err != nil {
+		return err
+	}
+
+	if err := c.Manager().Sync(); err != nil {
+		return err
+	}
+
+	return c.Manager().ScaleUp(events)
+}
+
+// scaleDownPhase removes nodes from the cluster and destroys them, while
+// new nodes are not provisioned yet. Throughout the phase, the new
+// configuration is temporarily replaced with the one that contains
+// the applied Kubernetes version and excludes new nodes.
+func (c *Cluster) scaleDownPhase(events event.Events) error {
+	newCfg := *c.NewConfig
+	defer func() { *c.NewConfig = newCfg }()
+
+	*c.NewConfig = withoutNewNodes(newCfg, events)
+	c.NewConfig.Kubernetes.Version = c.AppliedConfig.Kubernetes.Version
+
+	if err := c.Manager().Init(); err != nil {
+		return err
+	}
+
+	if err := c.Manager().ScaleDown(events); err != nil {
+		return err
+	}
+
+	if err := c.provision(events); err != nil {
+		return err
+	}
+
+	return c.Sync()
+}
+
+// upgradePhase upgrades existing nodes to the new Kubernetes version.
+// Throughout the phase, the new configuration is temporarily replaced
+// with the one that excludes new nodes, since they are not provisioned
+// yet.
+func (c *Cluster) upgradePhase(events event.Events) error {
+	newCfg := *c.NewConfig
+	defer func() { *c.NewConfig = newCfg }()
+
+	*c.NewConfig = withoutNewNodes(newCfg, events)
+
+	if err := c.Manager().Sync(); err != nil {
+		return err
+	}
+
+	return c.Manager().Upgrade()
+}
+
+// withoutNewNodes returns a copy of the given configuration without node
+// instances that are added by the scale up events.
+func withoutNewNodes(cfg config.Config, events event.Events) config.Config {
+	var newNodes []config.Instance
+	for _, e := range events.FilterByAction(event.Action_ScaleUp) {
+		if n, ok := e.Change.ValueAfter.(config.Instance); ok {
+			newNodes = append(newNodes, n)
+		}
+	}
+
+	isNew := func(i config.Instance) bool {
+		return slices.ContainsFunc(newNodes, func(n config.Instance) bool {
+			return n.GetTypeName() == i.GetTypeName() && n.GetID() == i.GetID()
+		})
+	}
+
+	nodes := &cfg.Cluster.Nodes
+	nodes.Master.Instances = slices.DeleteFunc(slices.Clone(nodes.Master.Instances), func(i config.MasterInstance) bool { return isNew(i) })
+	nodes.Worker.Instances = slices.DeleteFunc(slices.Clone(nodes.Worker.Instances), func(i config.WorkerInstance) bool { return isNew(i) })
+	nodes.LoadBalancer.Instances = slices.DeleteFunc(slices.Clone(nodes.LoadBalancer.Instances), func(i config.LBInstance) bool { return isNew(i) })
+
+	return cfg
+}
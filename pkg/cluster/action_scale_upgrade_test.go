@@ -0,0 +1,102 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upgradeManager records worker nodes that are present in the new
+// configuration when the cluster is upgraded.
+type upgradeManager struct {
+	interfaces.Manager
+	cfg     *config.Config
+	workers *[]string
+}
+
+func (m upgradeManager) Upgrade() error {
+	for _, n := range m.cfg.Cluster.Nodes.Worker.Instances {
+		*m.workers = append(*m.workers, n.GetID())
+	}
+
+	return nil
+}
+
+func TestApply_ScaleUpgrade(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	var upgraded []string
+	c.exec = upgradeManager{c.exec, c.NewConfig, &upgraded}
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.28.10")
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	require.NoError(t, c.Apply(SCALE_UPGRADE.String()))
+
+	out := c.Ui().ReadStdout(t)
+	assert.Contains(t, out, "phase 1/3")
+	assert.Contains(t, out, "phase 2/3")
+	assert.Contains(t, out, "phase 3/3")
+
+	// New nodes are not upgraded, since they are not provisioned yet.
+	assert.Empty(t, upgraded)
+
+	// New configuration is restored after the scale down phase.
+	assert.Equal(t, config.KubernetesVersion("v1.28.10"), c.NewConfig.Kubernetes.Version)
+	assert.Len(t, c.NewConfig.Cluster.Nodes.Worker.Instances, 1)
+
+	// New configuration is applied.
+	require.NoError(t, c.Sync())
+	assert.Equal(t, config.KubernetesVersion("v1.28.10"), c.AppliedConfig.Kubernetes.Version)
+	assert.Len(t, c.AppliedConfig.Cluster.Nodes.Worker.Instances, 1)
+}
+
+func TestApply_ScaleUpgrade_InvalidChange(t *testing.T) {
+	c := MockCluster(t)
+
+	assert.NoError(t, c.ApplyNewConfig())
+	assert.NoError(t, c.Sync())
+
+	c.NewConfig.Addons.Rook.Enabled = true
+
+	err := c.Apply(SCALE_UPGRADE.String())
+	assert.EqualError(t, err, "Configuration file contains errors.")
+}
+
+func TestWithoutNewNodes(t *testing.T) {
+	cfg := config.Config{}
+	cfg.Cluster.Nodes.Worker.Instances = []config.WorkerInstance{{Id: "1"}, {Id: "2"}}
+	cfg.Cluster.Nodes.LoadBalancer.Instances = []config.LBInstance{{Id: "2"}}
+
+	events := event.Events{
+		{
+			Rule:   event.Rule{ActionType: event.Action_ScaleUp},
+			Change: cmp.Change{ValueAfter: config.WorkerInstance{Id: "2"}},
+		},
+	}
+
+	res := withoutNewNodes(cfg, events)
+	assert.Equal(t, []config.WorkerInstance{{Id: "1"}}, res.Cluster.Nodes.Worker.Instances)
+	assert.Equal(t, []config.LBInstance{{Id: "2"}}, res.Cluster.Nodes.LoadBalancer.Instances)
+
+	// Original configuration is not modified.
+	assert.Len(t, cfg.Cluster.Nodes.Worker.Instances, 2)
+}
@@ -0,0 +1,298 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+)
+
+// etcdSnapshotPath is the path on control plane nodes to which the etcd
+// snapshot is saved before it is downloaded, or uploaded before it is
+// restored.
+const etcdSnapshotPath = "/tmp/kubitect-etcd-snapshot.db"
+
+var backupNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Backup describes a snapshot of the cluster's etcd database.
+type Backup struct {
+	Name              string    `json:"name" yaml:"name"`
+	Created           time.Time `json:"created" yaml:"created"`
+	KubernetesVersion string    `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	KubernetesManager string    `json:"kubernetesManager" yaml:"kubernetesManager"`
+	Node              string    `json:"node" yaml:"node"`
+	Members           []string  `json:"members" yaml:"members"`
+	Size              int64     `json:"size" yaml:"size"`
+}
+
+// Backups returns all backups of the cluster ordered by their creation
+// time.
+func (c ClusterMeta) Backups() ([]Backup, error) {
+	entries, err := os.ReadDir(c.BackupDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read cluster backups: %v", err)
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		if !e.IsDir() || !file.Exists(c.BackupPath(e.Name())) {
+			continue
+		}
+
+		b, err := c.Backup(e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, *b)
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return a.Created.Compare(b.Created)
+	})
+
+	return backups, nil
+}
+
+// Backup returns the backup with the given name.
+func (c ClusterMeta) Backup(name string) (*Backup, error) {
+	path := c.BackupPath(name)
+
+	if !backupNameRegex.MatchString(name) || !file.Exists(path) {
+		return nil, fmt.Errorf("backup %q of cluster %q does not exist", name, c.Name)
+	}
+
+	b, err := file.ReadYaml(path, Backup{})
+	if err != nil {
+		return nil, fmt.Errorf("read backup %q: %v", name, err)
+	}
+
+	return b, nil
+}
+
+// CreateBackup takes a snapshot of the etcd database on the first control
+// plane node and stores it among the cluster backups, along with the
+// backup metadata. If the name is empty, the backup is named after its
+// creation time.
+func (c *Cluster) CreateBackup(name string) (*Backup, error) {
+	b := Backup{
+		Name:              name,
+		Created:           time.Now().UTC().Truncate(time.Second),
+		KubernetesVersion: string(c.NewConfig.Kubernetes.Version),
+		KubernetesManager: string(c.NewConfig.Kubernetes.Manager),
+	}
+
+	if b.Name == "" {
+		b.Name = b.Created.Format("20060102-150405")
+	}
+
+	if !backupNameRegex.MatchString(b.Name) {
+		return nil, fmt.Errorf("invalid backup name %q (only alphanumeric characters, '.', '_' and '-' are allowed)", b.Name)
+	}
+
+	if file.Exists(c.BackupPath(b.Name)) {
+		return nil, fmt.Errorf("backup %q of cluster %q already exists", b.Name, c.Name)
+	}
+
+	masters := c.SelectNodes([]NodeSelector{{"role", "master"}})
+	if len(masters) == 0 {
+		return nil, fmt.Errorf("cluster %q has no control plane nodes", c.Name)
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	n := masters[0]
+	b.Node = c.nodeName(n)
+
+	ui.Printf(ui.INFO, "Creating etcd snapshot on node %q...\n", b.Node)
+
+	client := newNodeClient(c, string(n.GetIP()))
+	defer client.Close()
+
+	ctx := context.Background()
+	e := c.etcd()
+
+	out, err := client.OutputCtx(ctx, "sudo", "sh", "-c", exec.ShellQuote(e.members()))
+	if err != nil {
+		return nil, fmt.Errorf("list etcd members on node %q: %v", b.Node, err)
+	}
+
+	b.Members = strings.Fields(string(out))
+
+	defer removeEtcdSnapshot(client)
+
+	if err := runScript(ctx, client, e.snapshot(etcdSnapshotPath)); err != nil {
+		return nil, fmt.Errorf("create etcd snapshot on node %q: %v", b.Node, err)
+	}
+
+	ui.Printf(ui.INFO, "Downloading etcd snapshot from node %q...\n", b.Node)
+
+	if err := c.downloadSnapshot(ctx, client, &b); err != nil {
+		os.RemoveAll(filepath.Dir(c.BackupPath(b.Name)))
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// downloadSnapshot downloads the etcd snapshot from the node and writes
+// it along with the backup metadata into the backup directory.
+func (c *Cluster) downloadSnapshot(ctx context.Context, client nodeClient, b *Backup) error {
+	err := os.MkdirAll(filepath.Dir(c.BackupPath(b.Name)), 0744)
+	if err != nil {
+		return fmt.Errorf("create backup directory: %v", err)
+	}
+
+	f, err := os.OpenFile(c.BackupSnapshotPath(b.Name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("create snapshot file: %v", err)
+	}
+
+	defer f.Close()
+
+	client.SetStdout(f)
+	defer client.SetStdout(ui.Streams().Out().File())
+
+	if err := client.RunCtx(ctx, "sudo", "cat", etcdSnapshotPath); err != nil {
+		return fmt.Errorf("download etcd snapshot from node %q: %v", b.Node, err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("read snapshot file: %v", err)
+	}
+
+	b.Size = stat.Size()
+
+	err = file.WriteYaml(b, c.BackupPath(b.Name), 0644)
+	if err != nil {
+		return fmt.Errorf("write backup %q: %v", b.Name, err)
+	}
+
+	return nil
+}
+
+// RestoreBackup restores the etcd database of the cluster from the backup
+// with the given name. The control plane is stopped on all control plane
+// nodes, after which the snapshot is restored on each etcd member and the
+// control plane is started again.
+func (c *Cluster) RestoreBackup(name string) error {
+	b, err := c.Backup(name)
+	if err != nil {
+		return err
+	}
+
+	if b.KubernetesManager != string(c.NewConfig.Kubernetes.Manager) {
+		return fmt.Errorf("backup %q has been created by Kubernetes manager %q, but the cluster is managed by %q", b.Name, b.KubernetesManager, c.NewConfig.Kubernetes.Manager)
+	}
+
+	if b.KubernetesVersion != string(c.NewConfig.Kubernetes.Version) {
+		ui.Printf(ui.WARN, "Backup %q has been created with Kubernetes version %s, but the cluster runs version %s.\n",
+			b.Name, b.KubernetesVersion, c.NewConfig.Kubernetes.Version)
+	}
+
+	masters := c.SelectNodes([]NodeSelector{{"role", "master"}})
+	if len(masters) == 0 {
+		return fmt.Errorf("cluster %q has no control plane nodes", c.Name)
+	}
+
+	err = ui.Ask(fmt.Sprintf("Control plane of cluster %q will be stopped and its etcd data replaced with backup %q. Proceed?", c.Name, b.Name))
+	if err != nil {
+		return err
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	ctx := context.Background()
+	e := c.etcd()
+
+	clients := make([]nodeClient, len(masters))
+	for i, n := range masters {
+		clients[i] = newNodeClient(c, string(n.GetIP()))
+		defer clients[i].Close()
+	}
+
+	for i, n := range masters {
+		ui.Printf(ui.INFO, "Uploading etcd snapshot to node %q...\n", c.nodeName(n))
+
+		defer removeEtcdSnapshot(clients[i])
+
+		if err := c.uploadSnapshot(ctx, clients[i], b.Name); err != nil {
+			return fmt.Errorf("upload etcd snapshot to node %q: %v", c.nodeName(n), err)
+		}
+	}
+
+	steps := []struct {
+		msg    string
+		script func(first bool) string
+	}{
+		{"Stopping control plane", func(bool) string { return e.stop() }},
+		{"Restoring etcd snapshot", func(first bool) string { return e.restore(etcdSnapshotPath, first) }},
+		{"Starting control plane", e.start},
+	}
+
+	for _, s := range steps {
+		for i, n := range masters {
+			ui.Printf(ui.INFO, "%s on node %q...\n", s.msg, c.nodeName(n))
+
+			if err := runScript(ctx, clients[i], s.script(i == 0)); err != nil {
+				return fmt.Errorf("%s on node %q: %v", strings.ToLower(s.msg), c.nodeName(n), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// uploadSnapshot uploads the etcd snapshot of the backup with the given
+// name to the node.
+func (c *Cluster) uploadSnapshot(ctx context.Context, client nodeClient, name string) error {
+	f, err := os.Open(c.BackupSnapshotPath(name))
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	client.SetStdin(f)
+	defer client.SetStdin(nil)
+
+	return runScript(ctx, client, fmt.Sprintf("cat > %s", etcdSnapshotPath))
+}
+
+// runScript runs the shell script on the node with superuser privileges.
+// Output of the script is written to the UI streams.
+func runScript(ctx context.Context, client nodeClient, script string) error {
+	client.SetStdout(ui.Streams().Out().File())
+	client.SetStderr(ui.Streams().Err().File())
+
+	return client.RunCtx(ctx, "sudo", "sh", "-c", exec.ShellQuote(script))
+}
+
+// removeEtcdSnapshot removes the etcd snapshot from the node. Errors are
+// ignored, since the snapshot is stored in a temporary directory.
+func removeEtcdSnapshot(client nodeClient) {
+	client.OutputCtx(context.Background(), "sudo", "rm", "-f", etcdSnapshotPath)
+}
@@ -0,0 +1,189 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backupClientMock records executed commands and simulates an etcd
+// snapshot stored on the node.
+type backupClientMock struct {
+	nodeClientMock
+
+	ip       string
+	commands *[]string
+	snapshot map[string]string
+}
+
+func (m *backupClientMock) RunCtx(ctx context.Context, command string, args ...string) error {
+	cmd := strings.Join(append([]string{command}, args...), " ")
+	*m.commands = append(*m.commands, fmt.Sprintf("%s: %s", m.ip, cmd))
+
+	switch {
+	case cmd == "sudo cat "+etcdSnapshotPath:
+		_, err := io.WriteString(m.stdout, m.snapshot[m.ip])
+		return err
+	case strings.Contains(cmd, "cat > "+etcdSnapshotPath):
+		b, err := io.ReadAll(m.stdin)
+		m.snapshot[m.ip] = string(b)
+		return err
+	case strings.Contains(cmd, "snapshot save"):
+		m.snapshot[m.ip] = "snapshot"
+	}
+
+	return nil
+}
+
+func (m *backupClientMock) OutputCtx(ctx context.Context, command string, args ...string) ([]byte, error) {
+	cmd := strings.Join(append([]string{command}, args...), " ")
+	*m.commands = append(*m.commands, fmt.Sprintf("%s: %s", m.ip, cmd))
+
+	if strings.Contains(cmd, "member list") {
+		return []byte("etcd1\netcd2\n"), nil
+	}
+
+	return nil, nil
+}
+
+// mockBackup replaces node clients with backupClientMock for the duration
+// of the test and returns the list of executed commands, along with the
+// snapshots stored on nodes.
+func mockBackup(t *testing.T) (*[]string, map[string]string) {
+	t.Helper()
+
+	tmp := newNodeClient
+
+	var commands []string
+	snapshots := make(map[string]string)
+
+	newNodeClient = func(c *Cluster, ip string) nodeClient {
+		return &backupClientMock{ip: ip, commands: &commands, snapshot: snapshots}
+	}
+
+	t.Cleanup(func() { newNodeClient = tmp })
+
+	return &commands, snapshots
+}
+
+func TestBackups_Empty(t *testing.T) {
+	c := MockCluster(t)
+
+	backups, err := c.Backups()
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	_, err = c.Backup("daily")
+	assert.EqualError(t, err, `backup "daily" of cluster "cluster-mock" does not exist`)
+}
+
+func TestCreateBackup(t *testing.T) {
+	c := mockStatusCluster(t)
+	commands, snapshots := mockBackup(t)
+
+	b, err := c.CreateBackup("")
+	require.NoError(t, err)
+
+	assert.Equal(t, b.Created.Format("20060102-150405"), b.Name)
+	assert.Equal(t, c.Name+"-master-1", b.Node)
+	assert.Equal(t, []string{"etcd1", "etcd2"}, b.Members)
+	assert.Equal(t, string(c.NewConfig.Kubernetes.Version), b.KubernetesVersion)
+	assert.Equal(t, config.ManagerKubespray, b.KubernetesManager)
+	assert.Equal(t, int64(len("snapshot")), b.Size)
+
+	snapshot, err := os.ReadFile(c.BackupSnapshotPath(b.Name))
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot", string(snapshot))
+
+	backups, err := c.Backups()
+	require.NoError(t, err)
+	assert.Equal(t, []Backup{*b}, backups)
+
+	// Snapshot is removed from the node.
+	last := (*commands)[len(*commands)-1]
+	assert.Equal(t, "10.10.0.10: sudo rm -f "+etcdSnapshotPath, last)
+	assert.Contains(t, snapshots, "10.10.0.10")
+}
+
+func TestCreateBackup_Invalid(t *testing.T) {
+	c := mockStatusCluster(t)
+	mockBackup(t)
+
+	_, err := c.CreateBackup("../daily")
+	assert.EqualError(t, err, `invalid backup name "../daily" (only alphanumeric characters, '.', '_' and '-' are allowed)`)
+
+	_, err = c.CreateBackup("daily")
+	require.NoError(t, err)
+
+	_, err = c.CreateBackup("daily")
+	assert.EqualError(t, err, `backup "daily" of cluster "cluster-mock" already exists`)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	c := mockStatusCluster(t)
+	commands, snapshots := mockBackup(t)
+
+	_, err := c.CreateBackup("daily")
+	require.NoError(t, err)
+
+	masters := &c.InfraConfig.Nodes.Master
+	masters.Instances = append(masters.Instances, config.MasterInstance{Id: "2", IP: "10.10.0.11"})
+
+	*commands = nil
+	require.NoError(t, c.RestoreBackup("daily"))
+
+	assert.Equal(t, "snapshot", snapshots["10.10.0.10"])
+	assert.Equal(t, "snapshot", snapshots["10.10.0.11"])
+
+	e := kubesprayEtcd{}
+	script := func(s string) string {
+		return "sudo sh -c " + exec.ShellQuote(s)
+	}
+
+	expect := []string{
+		"10.10.0.10: " + script("cat > "+etcdSnapshotPath),
+		"10.10.0.11: " + script("cat > "+etcdSnapshotPath),
+		"10.10.0.10: " + script(e.stop()),
+		"10.10.0.11: " + script(e.stop()),
+		"10.10.0.10: " + script(e.restore(etcdSnapshotPath, true)),
+		"10.10.0.11: " + script(e.restore(etcdSnapshotPath, false)),
+		"10.10.0.10: " + script(e.start(true)),
+		"10.10.0.11: " + script(e.start(false)),
+		"10.10.0.11: sudo rm -f " + etcdSnapshotPath,
+		"10.10.0.10: sudo rm -f " + etcdSnapshotPath,
+	}
+
+	assert.Equal(t, expect, *commands)
+}
+
+func TestRestoreBackup_ManagerMismatch(t *testing.T) {
+	c := mockStatusCluster(t)
+	mockBackup(t)
+
+	_, err := c.CreateBackup("daily")
+	require.NoError(t, err)
+
+	c.NewConfig.Kubernetes.Manager = config.ManagerK3s
+
+	err = c.RestoreBackup("daily")
+	assert.EqualError(t, err, `backup "daily" has been created by Kubernetes manager "kubespray", but the cluster is managed by "k3s"`)
+}
+
+func TestEtcd_K3s(t *testing.T) {
+	c := MockCluster(t)
+	c.NewConfig.Kubernetes.Manager = config.ManagerK3s
+
+	e := c.etcd()
+	assert.Equal(t, k3sEtcd{}, e)
+	assert.Contains(t, e.restore("/tmp/snap.db", true), "--cluster-reset-restore-path='/tmp/snap.db'")
+	assert.NotContains(t, e.restore("/tmp/snap.db", false), "--cluster-reset")
+}
@@ -0,0 +1,205 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/certs"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// Directories containing certificates of the control plane on master
+// nodes. Kubespray links the kubeadm certificate directory to its own.
+const (
+	kubesprayCertsDir = "/etc/kubernetes/pki"
+	k3sCertsDir       = "/var/lib/rancher/k3s/server/tls"
+)
+
+// Certificate describes a certificate and its expiration.
+type Certificate struct {
+	Path     string    `json:"path" yaml:"path"`
+	Subject  string    `json:"subject" yaml:"subject"`
+	NotAfter time.Time `json:"notAfter" yaml:"notAfter"`
+}
+
+// NodeCertificates are control plane certificates of a single master node.
+type NodeCertificates struct {
+	Node         string        `json:"node" yaml:"node"`
+	Certificates []Certificate `json:"certificates" yaml:"certificates"`
+	Error        string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ClusterCertificates are control plane certificates of all master nodes,
+// along with the client certificate embedded in the cluster's kubeconfig.
+type ClusterCertificates struct {
+	Cluster string `json:"cluster" yaml:"cluster"`
+
+	Kubeconfig      *Certificate `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`
+	KubeconfigError string       `json:"kubeconfigError,omitempty" yaml:"kubeconfigError,omitempty"`
+
+	Nodes []NodeCertificates `json:"nodes" yaml:"nodes"`
+}
+
+// CheckCertificates reads control plane certificates on each master node
+// over SSH, as well as the client certificate from the cluster's
+// kubeconfig. Nodes are checked concurrently and each check must finish
+// within the given timeout.
+func (c *Cluster) CheckCertificates(timeout time.Duration) *ClusterCertificates {
+	cc := &ClusterCertificates{
+		Cluster: c.Name,
+	}
+
+	masters := c.SelectNodes([]NodeSelector{{"role", "master"}})
+	cc.Nodes = make([]NodeCertificates, len(masters))
+
+	var wg sync.WaitGroup
+	for i, n := range masters {
+		wg.Add(1)
+		go func(i int, n config.Instance) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			cc.Nodes[i] = c.nodeCertificates(ctx, n)
+		}(i, n)
+	}
+
+	cert, err := c.kubeconfigCertificate()
+	if err != nil {
+		cc.KubeconfigError = err.Error()
+	}
+
+	cc.Kubeconfig = cert
+
+	wg.Wait()
+
+	return cc
+}
+
+// nodeCertificates reads control plane certificates of the given node.
+func (c *Cluster) nodeCertificates(ctx context.Context, n config.Instance) NodeCertificates {
+	nc := NodeCertificates{
+		Node: c.nodeName(n),
+	}
+
+	dir := kubesprayCertsDir
+	if c.NewConfig.Kubernetes.Manager == config.ManagerK3s {
+		dir = k3sCertsDir
+	}
+
+	// Each certificate file is printed after a line with its path.
+	script := fmt.Sprintf(`find -L %s -type f -name '*.crt' | sort | while read -r f; do echo "# $f"; cat "$f"; done`, dir)
+
+	client := newNodeClient(c, string(n.GetIP()))
+	defer client.Close()
+
+	out, err := client.OutputCtx(ctx, "sudo", "sh", "-c", exec.ShellQuote(script))
+	if err != nil {
+		nc.Error = err.Error()
+		return nc
+	}
+
+	nc.Certificates = parseCertificates(out)
+
+	return nc
+}
+
+// parseCertificates parses certificates from the output in which each
+// certificate file is preceded by a line with its path. Files that do not
+// contain a certificate are skipped.
+func parseCertificates(out []byte) []Certificate {
+	var crts []Certificate
+	var path string
+	var data bytes.Buffer
+
+	flush := func() {
+		if path == "" {
+			return
+		}
+
+		crt, err := certs.Parse(data.Bytes())
+		if err == nil {
+			crts = append(crts, Certificate{
+				Path:     path,
+				Subject:  crt.Subject.CommonName,
+				NotAfter: crt.NotAfter,
+			})
+		}
+
+		data.Reset()
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		line := s.Text()
+
+		if p, ok := strings.CutPrefix(line, "# "); ok {
+			flush()
+			path = p
+			continue
+		}
+
+		data.WriteString(line + "\n")
+	}
+
+	flush()
+
+	return crts
+}
+
+// kubeconfigCertificate returns the client certificate embedded in the
+// cluster's kubeconfig.
+func (c *Cluster) kubeconfigCertificate() (*Certificate, error) {
+	if !c.ContainsKubeconfig() {
+		return nil, fmt.Errorf("cluster %q does not have a Kubeconfig file", c.Name)
+	}
+
+	kubeconfig, err := os.ReadFile(c.KubeconfigPath())
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := certs.KubeconfigCredentials(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	crt, err := creds.Certificate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Certificate{
+		Path:     c.KubeconfigPath(),
+		Subject:  crt.Subject.CommonName,
+		NotAfter: crt.NotAfter,
+	}, nil
+}
+
+// RenewCertificates renews certificates of the control plane on all master
+// nodes using the mechanism of the cluster's Kubernetes manager, and
+// refreshes the client credentials in the cluster's kubeconfig.
+func (c *Cluster) RenewCertificates() error {
+	err := ui.Ask(fmt.Sprintf("Control plane certificates of cluster %q will be renewed, which restarts the control plane on each master node. Proceed?", c.Name))
+	if err != nil {
+		return err
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	return c.Manager().RenewCertificates()
+}
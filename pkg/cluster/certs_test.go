@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockCertificate(t *testing.T, cn string, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestParseCertificates(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	out := "# /pki/apiserver.crt\n" + mockCertificate(t, "kube-apiserver", notAfter) +
+		"# /pki/invalid.crt\ninvalid\n" +
+		"# /pki/ca.crt\n" + mockCertificate(t, "kubernetes", notAfter.AddDate(9, 0, 0))
+
+	crts := parseCertificates([]byte(out))
+	assert.Equal(t, []Certificate{
+		{Path: "/pki/apiserver.crt", Subject: "kube-apiserver", NotAfter: notAfter},
+		{Path: "/pki/ca.crt", Subject: "kubernetes", NotAfter: notAfter.AddDate(9, 0, 0)},
+	}, crts)
+}
+
+func TestCheckCertificates(t *testing.T) {
+	c := mockStatusCluster(t)
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	script := fmt.Sprintf(`find -L %s -type f -name '*.crt' | sort | while read -r f; do echo "# $f"; cat "$f"; done`, kubesprayCertsDir)
+	cmd := "sudo sh -c " + exec.ShellQuote(script)
+
+	mockStatus(t, map[string]nodeClientMock{
+		"10.10.0.10": {outputs: map[string]string{
+			cmd: "# /etc/kubernetes/pki/apiserver.crt\n" + mockCertificate(t, "kube-apiserver", notAfter),
+		}},
+	}, "")
+
+	kubeconfig := fmt.Sprintf("users:\n- user:\n    client-certificate-data: %s\n",
+		base64.StdEncoding.EncodeToString([]byte(mockCertificate(t, "kubernetes-admin", notAfter))))
+
+	require.NoError(t, os.MkdirAll(c.ConfigDir(), 0700))
+	require.NoError(t, os.WriteFile(c.KubeconfigPath(), []byte(kubeconfig), 0600))
+
+	cc := c.CheckCertificates(time.Second)
+
+	assert.Equal(t, &ClusterCertificates{
+		Cluster: c.Name,
+		Kubeconfig: &Certificate{
+			Path:     c.KubeconfigPath(),
+			Subject:  "kubernetes-admin",
+			NotAfter: notAfter,
+		},
+		Nodes: []NodeCertificates{
+			{
+				Node: c.Name + "-master-1",
+				Certificates: []Certificate{
+					{Path: "/etc/kubernetes/pki/apiserver.crt", Subject: "kube-apiserver", NotAfter: notAfter},
+				},
+			},
+		},
+	}, cc)
+}
+
+func TestCheckCertificates_Unreachable(t *testing.T) {
+	c := mockStatusCluster(t)
+	mockStatus(t, map[string]nodeClientMock{}, "")
+
+	cc := c.CheckCertificates(time.Second)
+
+	require.Len(t, cc.Nodes, 1)
+	assert.NotEmpty(t, cc.Nodes[0].Error)
+	assert.Nil(t, cc.Kubeconfig)
+	assert.Equal(t, `cluster "cluster-mock" does not have a Kubeconfig file`, cc.KubeconfigError)
+}
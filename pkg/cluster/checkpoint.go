@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+)
+
+// Checkpoint records the progress of an apply. It allows a failed apply
+// to be resumed from the phase that has failed, instead of starting over.
+type Checkpoint struct {
+	Action     ApplyAction `yaml:"action"`
+	ConfigHash string      `yaml:"configHash"`
+
+	// Phases contains names of completed phases in the order in which
+	// they have been completed.
+	Phases []string `yaml:"phases"`
+}
+
+// checkpoint tracks the progress of an ongoing apply.
+type checkpoint struct {
+	Checkpoint
+
+	path string
+
+	// previous contains phases completed by the previous apply, which
+	// are skipped while the apply is being resumed.
+	previous []string
+}
+
+// run runs the given function as a named phase and records its
+// completion. The phase is skipped if the previous apply has completed
+// it at the same point of the apply.
+func (cp *checkpoint) run(name string, fn func() error) error {
+	i := len(cp.Phases)
+
+	if i < len(cp.previous) && cp.previous[i] == name {
+		ui.Printf(ui.INFO, "Skipping phase %q (completed by the previous apply)...\n", name)
+	} else {
+		// Once the apply diverges from the previous one, no further
+		// phases are skipped.
+		cp.previous = nil
+
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	cp.Phases = append(cp.Phases, name)
+	return cp.write()
+}
+
+// write writes the checkpoint into the cluster directory.
+func (cp *checkpoint) write() error {
+	err := file.WriteYaml(cp.Checkpoint, cp.path, 0644)
+	if err != nil {
+		return fmt.Errorf("write apply checkpoint: %v", err)
+	}
+
+	return nil
+}
+
+// readCheckpoint reads the checkpoint on the given path. If checkpoint
+// does not exist, nil is returned both for an error and the checkpoint.
+func readCheckpoint(path string) (*Checkpoint, error) {
+	if !file.Exists(path) {
+		return nil, nil
+	}
+
+	cp, err := file.ReadYaml(path, Checkpoint{})
+	if err != nil {
+		return nil, fmt.Errorf("read apply checkpoint: %v", err)
+	}
+
+	return cp, nil
+}
+
+// startCheckpoint starts recording the progress of the apply. If resume
+// is true, phases completed by the previous apply are skipped, as long as
+// the previous apply has been executed with the same action and the same
+// configuration. Otherwise, any existing checkpoint is discarded.
+//
+// The new configuration must already be stored in the cluster directory.
+func (c *Cluster) startCheckpoint(action ApplyAction, resume bool) error {
+	hash, err := fileHash(c.NewConfigPath)
+	if err != nil {
+		return err
+	}
+
+	prev, err := readCheckpoint(c.CheckpointPath())
+	if err != nil {
+		return err
+	}
+
+	cp := &checkpoint{
+		Checkpoint: Checkpoint{
+			Action:     action,
+			ConfigHash: hash,
+		},
+		path: c.CheckpointPath(),
+	}
+
+	switch {
+	case !resume:
+		if prev != nil {
+			ui.Println(ui.INFO, "Previous apply has not been completed. Starting from the beginning (use '--resume' to continue from the failed phase)...")
+		}
+
+		err := os.Remove(c.CheckpointPath())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove apply checkpoint: %v", err)
+		}
+	case prev == nil:
+		ui.Println(ui.INFO, "No checkpoint of the previous apply has been found. Starting from the beginning...")
+	case prev.Action != action:
+		return fmt.Errorf("cannot resume apply: previous apply has been executed with action %q, not %q", prev.Action, action)
+	case prev.ConfigHash != hash:
+		return fmt.Errorf("cannot resume apply: configuration file has changed since the previous apply")
+	default:
+		ui.Printf(ui.INFO, "Resuming previous apply (%d completed phases)...\n", len(prev.Phases))
+		cp.previous = prev.Phases
+	}
+
+	c.checkpoint = cp
+	return nil
+}
+
+// clearCheckpoint stops recording the progress of the apply and removes
+// the checkpoint from the cluster directory.
+func (c *Cluster) clearCheckpoint() error {
+	c.checkpoint = nil
+
+	err := os.Remove(c.CheckpointPath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove apply checkpoint: %v", err)
+	}
+
+	return nil
+}
+
+// phase runs the given function as a named phase of the apply. While
+// the apply is being resumed, phases completed by the previous apply
+// are skipped.
+func (c *Cluster) phase(name string, fn func() error) error {
+	if c.checkpoint == nil {
+		return fn()
+	}
+
+	return c.checkpoint.run(name, fn)
+}
@@ -0,0 +1,124 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/provisioner"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingProvisioner is a provisioner whose apply fails.
+type failingProvisioner struct {
+	provisioner.Provisioner
+}
+
+func (p failingProvisioner) Apply() error {
+	return fmt.Errorf("apply failed")
+}
+
+func TestCheckpoint_Resume(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.prepare())
+
+	// Complete first phase and fail the second one.
+	require.NoError(t, c.startCheckpoint(CREATE, false))
+	require.NoError(t, c.phase("a", func() error { return nil }))
+	require.Error(t, c.phase("b", func() error { return fmt.Errorf("fail") }))
+
+	cp, err := readCheckpoint(c.CheckpointPath())
+	require.NoError(t, err)
+	assert.Equal(t, CREATE, cp.Action)
+	assert.Equal(t, []string{"a"}, cp.Phases)
+
+	// Completed phase is skipped on resume.
+	require.NoError(t, c.startCheckpoint(CREATE, true))
+	require.NoError(t, c.phase("a", func() error { return fmt.Errorf("should be skipped") }))
+	require.NoError(t, c.phase("b", func() error { return nil }))
+
+	cp, err = readCheckpoint(c.CheckpointPath())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, cp.Phases)
+	assert.Contains(t, c.Ui().ReadStdout(t), `Skipping phase "a"`)
+}
+
+func TestCheckpoint_NoResume(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.prepare())
+
+	require.NoError(t, c.startCheckpoint(CREATE, false))
+	require.NoError(t, c.phase("a", func() error { return nil }))
+
+	// Existing checkpoint is discarded if apply is not resumed.
+	require.NoError(t, c.startCheckpoint(CREATE, false))
+	assert.False(t, file.Exists(c.CheckpointPath()))
+
+	executed := false
+	require.NoError(t, c.phase("a", func() error { executed = true; return nil }))
+	assert.True(t, executed)
+}
+
+func TestCheckpoint_ResumeDiverged(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.prepare())
+
+	require.NoError(t, c.startCheckpoint(CREATE, false))
+	require.NoError(t, c.phase("a", func() error { return nil }))
+	require.NoError(t, c.phase("b", func() error { return nil }))
+
+	// Phases are not skipped after the apply diverges.
+	var executed []string
+	run := func(name string) func() error {
+		return func() error { executed = append(executed, name); return nil }
+	}
+
+	require.NoError(t, c.startCheckpoint(CREATE, true))
+	require.NoError(t, c.phase("c", run("c")))
+	require.NoError(t, c.phase("b", run("b")))
+	assert.Equal(t, []string{"c", "b"}, executed)
+}
+
+func TestCheckpoint_ResumeInvalid(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.prepare())
+
+	require.NoError(t, c.startCheckpoint(CREATE, false))
+	require.NoError(t, c.phase("a", func() error { return nil }))
+
+	err := c.startCheckpoint(SCALE, true)
+	assert.EqualError(t, err, `cannot resume apply: previous apply has been executed with action "create", not "scale"`)
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.26.5")
+	require.NoError(t, c.StoreNewConfig())
+
+	err = c.startCheckpoint(CREATE, true)
+	assert.EqualError(t, err, "cannot resume apply: configuration file has changed since the previous apply")
+}
+
+func TestApply_Resume(t *testing.T) {
+	c := MockCluster(t)
+	c.prov = failingProvisioner{c.prov}
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	require.EqualError(t, c.Apply(CREATE.String()), "apply failed")
+
+	cp, err := readCheckpoint(c.CheckpointPath())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssh-keys", "provisioner-init"}, cp.Phases)
+
+	// Checkpoint is removed once the configuration is applied.
+	c.prov = provisioner.MockProvisioner(t)
+	require.NoError(t, c.Resume(CREATE.String()))
+	assert.Contains(t, c.Ui().ReadStdout(t), "Resuming previous apply (2 completed phases)")
+	assert.False(t, file.Exists(c.CheckpointPath()))
+	assert.True(t, c.ContainsAppliedConfig())
+}
@@ -24,10 +24,21 @@ type Cluster struct {
 
 	NewConfigPath string
 
+	// PolicyPath is a path to the policy file provided by the user, whose
+	// rules are evaluated along with the built-in rules.
+	PolicyPath string
+
+	// Explain enables an explanation of how rules are matched with the
+	// detected changes when the plan is created.
+	Explain bool
+
 	// Configuration files
 	NewConfig     *config.Config
 	AppliedConfig *config.Config
 	InfraConfig   *infra.Config
+
+	// Progress of the ongoing apply.
+	checkpoint *checkpoint
 }
 
 // NewCluster returns new Cluster instance with populated general fields.
@@ -52,6 +63,14 @@ func NewCluster(ctx app.AppContext, configPath string) (*Cluster, error) {
 		return nil, fmt.Errorf("failed to set config defaults: %v", err)
 	}
 
+	// Policy file is referenced relatively to the configuration file.
+	policyFile, err := resolveConfigFile(configPath, string(c.NewConfig.PolicyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve policy file path: %v", err)
+	}
+
+	c.NewConfig.PolicyFile = config.File(policyFile)
+
 	if err := validateConfig(c.NewConfig); err != nil {
 		ui.PrintBlockE(err...)
 		return nil, fmt.Errorf("invalid configuration file")
@@ -73,6 +92,29 @@ func NewCluster(ctx app.AppContext, configPath string) (*Cluster, error) {
 	return c, c.Sync()
 }
 
+// OpenCluster returns an existing cluster described by the given metadata.
+// Applied configuration of the cluster is used as its new configuration,
+// which allows managing the cluster without the configuration file.
+func OpenCluster(meta ClusterMeta) (*Cluster, error) {
+	c := &Cluster{ClusterMeta: meta}
+
+	if err := c.Sync(); err != nil {
+		return nil, err
+	}
+
+	if c.AppliedConfig == nil {
+		return nil, fmt.Errorf("cluster %q has not been created yet", c.Name)
+	}
+
+	if c.InfraConfig == nil {
+		return nil, fmt.Errorf("infrastructure of cluster %q has not been provisioned yet", c.Name)
+	}
+
+	c.NewConfig = c.AppliedConfig
+
+	return c, nil
+}
+
 // Sync ensures that cluster configuration files are up to data.
 func (c *Cluster) Sync() error {
 	var err error
@@ -82,7 +124,16 @@ func (c *Cluster) Sync() error {
 		return fmt.Errorf("failed to read previously applied configuration file: %v", err)
 	}
 
-	if c.AppliedConfig != nil {
+	// Configuration applied by an older version may lack properties that
+	// have been introduced since. Setting defaults ensures such properties
+	// are not reported as changes when compared to the new configuration.
+	if appliedCfg != nil {
+		if err := defaults.Set(appliedCfg); err != nil {
+			return fmt.Errorf("failed to set applied config defaults: %v", err)
+		}
+	}
+
+	if c.AppliedConfig != nil && appliedCfg != nil {
 		*c.AppliedConfig = *appliedCfg
 	} else {
 		c.AppliedConfig = appliedCfg
@@ -127,6 +178,7 @@ func (c *Cluster) Manager() interfaces.Manager {
 			c.ShareDir(),
 			c.NewConfig,
 			c.InfraConfig,
+			c.phase,
 		)
 	case config.ManagerKubespray:
 		c.exec = managers.NewKubesprayManager(
@@ -138,6 +190,7 @@ func (c *Cluster) Manager() interfaces.Manager {
 			c.ShareDir(),
 			c.NewConfig,
 			c.InfraConfig,
+			c.phase,
 		)
 	}
 
@@ -2,7 +2,6 @@ package cluster
 
 import (
 	"fmt"
-	"os"
 	"path"
 	"testing"
 
@@ -13,6 +12,7 @@ import (
 	"github.com/MusicDin/kubitect/pkg/models/config"
 	"github.com/MusicDin/kubitect/pkg/ui"
 	"github.com/MusicDin/kubitect/pkg/utils/defaults"
+	"github.com/MusicDin/kubitect/pkg/utils/keygen"
 	"github.com/MusicDin/kubitect/pkg/utils/template"
 
 	"github.com/stretchr/testify/assert"
@@ -41,21 +41,53 @@ func MockCluster(t *testing.T) *ClusterMock {
 		Local:       false,
 		AutoApprove: true,
 	}
+
+	return mockCluster(t, ctxOptions)
+}
+
+// MockStructuredCluster returns a mock cluster that prints output in the
+// given structured format.
+func MockStructuredCluster(t *testing.T, format ui.OutputFormat) *ClusterMock {
+	t.Helper()
+
+	ctxOptions := app.AppContextOptions{
+		Local:       false,
+		AutoApprove: true,
+		Output:      format,
+	}
+
+	return mockCluster(t, ctxOptions)
+}
+
+func mockCluster(t *testing.T, ctxOptions app.AppContextOptions) *ClusterMock {
+	t.Helper()
+
 	ctx := app.MockAppContext(t, ctxOptions)
 
 	c, err := NewCluster(ctx, ConfigMock{}.Write(t))
 	require.NoError(t, err)
 
-	// Create an empty SSH key pair
+	// Create an SSH key pair. Ed25519 keys are generated instantly.
 	keyDir := t.TempDir()
 	keyPath := path.Join(keyDir, "key")
-	os.Create(keyPath)
-	os.Create(keyPath + ".pub")
+
+	kp, err := keygen.NewKeyPairOfType(keygen.ED25519, 0)
+	require.NoError(t, err)
+	require.NoError(t, kp.Write(keyDir, "key"))
+
 	c.NewConfig.Cluster.NodeTemplate.SSH.PrivateKeyPath = config.File(keyPath)
 
 	c.exec = interfaces.MockManager(t)
 	c.prov = provisioner.MockProvisioner(t)
 
+	// Infrastructure is planned with the mocked provisioner.
+	tmpPlanProv := newPlanProvisioner
+	newPlanProvisioner = func(c *Cluster, clusterPath string) provisioner.Provisioner {
+		return c.Provisioner()
+	}
+
+	t.Cleanup(func() { newPlanProvisioner = tmpPlanProv })
+
 	return &ClusterMock{c, ctx}
 }
 
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/MusicDin/kubitect/pkg/app"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/defaults"
 	"github.com/MusicDin/kubitect/pkg/utils/template"
 
 	"github.com/stretchr/testify/assert"
@@ -38,6 +40,22 @@ func TestNewCluster_ClusterNameAlreadyPrefixed(t *testing.T) {
 	assert.Equal(t, "local-cluster-mock", c.Name)
 }
 
+func TestNewCluster_RelativePolicyFile(t *testing.T) {
+	cfgPath := ConfigMock{}.Write(t)
+	policyPath := path.Join(path.Dir(cfgPath), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte("rules: []\n"), 0600))
+
+	f, err := os.OpenFile(cfgPath, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString("\npolicyFile: policy.yaml\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	c, err := NewCluster(app.MockAppContext(t), cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, config.File(policyPath), c.NewConfig.PolicyFile)
+}
+
 func TestNewCluster_InvalidClusterName(t *testing.T) {
 	cfg := ConfigMock{ClusterName: "local-cluster-mock"}
 	cfgPath := cfg.Write(t)
@@ -104,6 +122,37 @@ func TestSync_InvalidInfraConfig(t *testing.T) {
 	assert.ErrorContains(t, c.Sync(), "infrastructure file (produced by Terraform) is invalid")
 }
 
+// mockDefaultDataDisks adds a default data disk to master nodes and
+// applies defaults to the new configuration, as when the configuration
+// file is read.
+func mockDefaultDataDisks(t *testing.T, c *ClusterMock) {
+	t.Helper()
+
+	c.NewConfig.Cluster.Nodes.Master.Default.DataDisks = []config.DataDisk{
+		{Name: "data", Size: 10},
+	}
+
+	require.NoError(t, defaults.Set(c.NewConfig))
+}
+
+func TestSync_DefaultDataDisks(t *testing.T) {
+	c := MockCluster(t)
+	mockDefaultDataDisks(t, c)
+
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+	require.NoError(t, c.Sync())
+
+	disks := []config.DataDisk{{Name: "data", Size: 10}}
+	for _, n := range c.AppliedConfig.Cluster.Nodes.Master.Instances {
+		assert.Equal(t, disks, n.DataDisks)
+	}
+
+	patch, err := c.JsonPatch()
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+}
+
 func TestApplyNewConfig(t *testing.T) {
 	c := MockCluster(t)
 
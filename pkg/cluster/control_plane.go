@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+)
+
+// etcdQuorum returns the number of etcd members required for the quorum
+// of the etcd cluster with the given number of members.
+func etcdQuorum(members int) int {
+	return members/2 + 1
+}
+
+// etcdFaultTolerance returns the number of etcd members that can fail
+// without breaking the quorum of the etcd cluster with the given number
+// of members.
+func etcdFaultTolerance(members int) int {
+	return members - etcdQuorum(members)
+}
+
+// quorumEvents returns error events for removed control plane nodes if
+// their removal would leave the cluster with fewer control plane nodes
+// than required for the etcd quorum of the current cluster and would
+// reduce the fault tolerance of the etcd cluster. Since etcd member runs
+// on each control plane node, removing too many of them at once puts the
+// availability of the whole cluster at risk. A cluster that tolerates no
+// failures (e.g. 2 members) can still be scaled down, since its fault
+// tolerance is not reduced.
+func (c *Cluster) quorumEvents(events event.Events) event.Events {
+	if c.AppliedConfig == nil {
+		return nil
+	}
+
+	var removed event.Events
+	for _, e := range events.FilterByAction(event.Action_ScaleDown) {
+		n, ok := e.Change.ValueBefore.(config.Instance)
+		if ok && n.GetTypeName() == "master" {
+			removed = append(removed, e)
+		}
+	}
+
+	members := len(c.AppliedConfig.Cluster.Nodes.Master.Instances)
+	quorum := etcdQuorum(members)
+
+	if len(removed) == 0 || members-len(removed) >= quorum {
+		return nil
+	}
+
+	if etcdFaultTolerance(members-len(removed)) >= etcdFaultTolerance(members) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Removing %d of %d control plane nodes would break the etcd quorum. At least %d control plane nodes must remain in the cluster.", len(removed), members, quorum)
+
+	var qEvents event.Events
+	for _, e := range removed {
+		qEvents = append(qEvents, event.Event{
+			Rule: event.Rule{
+				Type:            event.Error,
+				MatchChangeType: cmp.Delete,
+				Message:         msg,
+			},
+			Change:             e.Change,
+			MatchedChangePaths: e.MatchedChangePaths,
+		})
+	}
+
+	return qEvents
+}
@@ -0,0 +1,129 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockControlPlane sets the given number of master instances in both
+// applied and new configuration.
+func mockControlPlane(t *testing.T, c *ClusterMock, masters int) {
+	t.Helper()
+
+	var instances []config.MasterInstance
+	for _, id := range []string{"1", "2", "3", "4", "5"}[:masters] {
+		instances = append(instances, mockMaster(c, id))
+	}
+
+	c.NewConfig.Cluster.Nodes.Master.Instances = instances
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+}
+
+// mockMaster returns a copy of the first master instance with the given id.
+func mockMaster(c *ClusterMock, id string) config.MasterInstance {
+	m := c.NewConfig.Cluster.Nodes.Master.Instances[0]
+	m.Id = id
+	return m
+}
+
+func TestEtcdQuorum(t *testing.T) {
+	assert.Equal(t, 1, etcdQuorum(1))
+	assert.Equal(t, 2, etcdQuorum(2))
+	assert.Equal(t, 2, etcdQuorum(3))
+	assert.Equal(t, 3, etcdQuorum(5))
+}
+
+func TestEtcdFaultTolerance(t *testing.T) {
+	assert.Equal(t, 0, etcdFaultTolerance(1))
+	assert.Equal(t, 0, etcdFaultTolerance(2))
+	assert.Equal(t, 1, etcdFaultTolerance(3))
+	assert.Equal(t, 1, etcdFaultTolerance(4))
+	assert.Equal(t, 2, etcdFaultTolerance(5))
+}
+
+func TestEvents_ScaleControlPlane(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 3)
+
+	// Add 2 and remove 1 master.
+	masters := &c.NewConfig.Cluster.Nodes.Master
+	masters.Instances = append(masters.Instances[1:], mockMaster(c, "4"), mockMaster(c, "5"))
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+	assert.Len(t, events.FilterByAction(event.Action_ScaleDown), 1)
+	assert.Len(t, events.FilterByAction(event.Action_ScaleUp), 2)
+	assert.Empty(t, events.FilterByRuleType(event.Error))
+}
+
+func TestEvents_ScaleControlPlane_QuorumLost(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 5)
+
+	// Remove 3 of 5 masters.
+	masters := &c.NewConfig.Cluster.Nodes.Master
+	masters.Instances = masters.Instances[:2]
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 3)
+	assert.Equal(t, "Removing 3 of 5 control plane nodes would break the etcd quorum. At least 3 control plane nodes must remain in the cluster.", errs[0].Rule.Message)
+	assert.Equal(t, "cluster.nodes.master.instances.3", errs[0].Change.Path)
+}
+
+func TestEvents_ScaleControlPlane_NoFaultTolerance(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 2)
+
+	// Remove 1 of 2 masters. Neither cluster tolerates a failed member.
+	masters := &c.NewConfig.Cluster.Nodes.Master
+	masters.Instances = masters.Instances[:1]
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+	assert.Len(t, events.FilterByAction(event.Action_ScaleDown), 1)
+	assert.Empty(t, events.FilterByRuleType(event.Error))
+}
+
+func TestEvents_ScaleControlPlane_FaultToleranceReduced(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 3)
+
+	// Remove 2 of 3 masters.
+	masters := &c.NewConfig.Cluster.Nodes.Master
+	masters.Instances = masters.Instances[:1]
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "Removing 2 of 3 control plane nodes would break the etcd quorum. At least 2 control plane nodes must remain in the cluster.", errs[0].Rule.Message)
+}
+
+func TestApply_ScaleControlPlane(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 3)
+
+	masters := &c.NewConfig.Cluster.Nodes.Master
+	masters.Instances = masters.Instances[:1]
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	assert.EqualError(t, c.Apply(SCALE.String()), "Configuration file contains errors.")
+
+	masters.Instances = append(masters.Instances, mockMaster(c, "2"), mockMaster(c, "4"))
+	assert.NoError(t, c.Apply(SCALE.String()))
+}
@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diff compares the applied configuration with the new one. If the cluster
+// has not been created yet, all values of the new configuration are reported
+// as created.
+func (c *Cluster) Diff() (*cmp.Result, error) {
+	return cmp.Compare(c.AppliedConfig, c.NewConfig, configCmpOptions)
+}
+
+// JsonPatch returns a JSON Patch (RFC 6902) that transforms the applied
+// configuration into the new one. If the cluster has not been created yet,
+// the patch adds the whole new configuration.
+func (c *Cluster) JsonPatch() ([]cmp.PatchOperation, error) {
+	var applied any
+	if c.AppliedConfig != nil {
+		doc, err := toDocument(c.AppliedConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		applied = doc
+	}
+
+	doc, err := toDocument(c.NewConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if applied == nil {
+		return []cmp.PatchOperation{{Op: cmp.PatchAdd, Path: "", Value: doc}}, nil
+	}
+
+	return cmp.JsonPatch(applied, doc), nil
+}
+
+// toDocument converts the given configuration into a generic document,
+// as it would be read from the configuration file.
+func toDocument(cfg *config.Config) (any, error) {
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// ConfigChange is a serializable representation of a configuration change.
+type ConfigChange struct {
+	Type   cmp.ChangeType `json:"type" yaml:"type"`
+	Path   string         `json:"path" yaml:"path"`
+	Before any            `json:"before,omitempty" yaml:"before,omitempty"`
+	After  any            `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// ConfigChanges returns serializable representations of the changes from
+// the given comparison result.
+func ConfigChanges(res *cmp.Result) []ConfigChange {
+	changes := make([]ConfigChange, 0)
+	for _, ch := range res.Changes() {
+		changes = append(changes, ConfigChange{
+			Type:   ch.Type,
+			Path:   ch.Path,
+			Before: ch.ValueBefore,
+			After:  ch.ValueAfter,
+		})
+	}
+
+	return changes
+}
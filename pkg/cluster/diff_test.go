@@ -0,0 +1,68 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	res, err := c.Diff()
+	require.NoError(t, err)
+	assert.False(t, res.HasChanges())
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM = 16
+
+	res, err = c.Diff()
+	require.NoError(t, err)
+	require.True(t, res.HasChanges())
+
+	out := res.ToYaml(cmp.FormatOptions{ShowDiffOnly: true})
+	assert.Contains(t, out, "ram: 4 -> 16")
+}
+
+func TestDiff_NewCluster(t *testing.T) {
+	c := MockCluster(t)
+
+	res, err := c.Diff()
+	require.NoError(t, err)
+	assert.True(t, res.HasChanges())
+
+	for _, ch := range res.Changes() {
+		assert.Equal(t, cmp.Create, ch.Type, ch.Path)
+	}
+}
+
+func TestJsonPatch(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM = 16
+
+	patch, err := c.JsonPatch()
+	require.NoError(t, err)
+
+	expect := []cmp.PatchOperation{
+		{Op: cmp.PatchReplace, Path: "/cluster/nodes/master/instances/0/ram", Value: 16},
+	}
+
+	assert.Equal(t, expect, patch)
+}
+
+func TestJsonPatch_NewCluster(t *testing.T) {
+	c := MockCluster(t)
+
+	patch, err := c.JsonPatch()
+	require.NoError(t, err)
+	require.Len(t, patch, 1)
+	assert.Equal(t, cmp.PatchAdd, patch[0].Op)
+	assert.Equal(t, "", patch[0].Path)
+}
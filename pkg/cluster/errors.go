@@ -1,6 +1,10 @@
 package cluster
 
-import "github.com/MusicDin/kubitect/pkg/ui"
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
 
 func NewInvalidClusterDirError(missingFiles []string) error {
 	return ui.NewErrorBlock(ui.ERROR,
@@ -15,7 +19,7 @@ func NewValidationError(msg string, path string) error {
 	return ui.NewErrorBlock(ui.ERROR,
 		[]ui.Content{
 			ui.NewErrorLine("Error type:", "Validation Error"),
-			ui.NewErrorSection("Config path:", path),
+			ui.NewErrorSection("Config path:", path).WithKey("namespace"),
 			ui.NewErrorSection("Error:", msg),
 		},
 	)
@@ -40,3 +44,16 @@ func NewConfigChangeWarning(msg string, paths ...string) error {
 		},
 	)
 }
+
+func NewClusterLockedError(clusterName string, lock Lock) error {
+	msg := fmt.Sprintf("Cluster %q is locked by another operation. Wait for it to finish or, if it is no longer running, use '--force-unlock' to remove the lock.", clusterName)
+
+	return ui.NewErrorBlock(ui.ERROR,
+		[]ui.Content{
+			ui.NewErrorLine("Error type:", "Cluster Locked"),
+			ui.NewErrorSection("Lock holder:", lock.String()),
+			ui.NewErrorSection("Command:", lock.Command),
+			ui.NewErrorSection("Error:", msg),
+		},
+	)
+}
@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// etcd provides shell scripts that manage the etcd cluster on control
+// plane nodes. Scripts are executed with superuser privileges.
+type etcd interface {
+	// snapshot saves a snapshot of the etcd database to the given path.
+	snapshot(path string) string
+
+	// members prints names of the etcd members, one per line.
+	members() string
+
+	// stop stops the control plane and etcd on the node.
+	stop() string
+
+	// restore replaces the etcd data on the node with the snapshot at
+	// the given path. The snapshot is restored on the first member, before
+	// it is restored on the remaining members.
+	restore(path string, first bool) string
+
+	// start starts etcd and the control plane on the node. The first member
+	// is started before the remaining members.
+	start(first bool) string
+}
+
+// etcd returns the etcd scripts of the cluster's Kubernetes manager.
+func (c *Cluster) etcd() etcd {
+	if c.NewConfig.Kubernetes.Manager == config.ManagerK3s {
+		return k3sEtcd{}
+	}
+
+	return kubesprayEtcd{}
+}
+
+// kubesprayEtcdctl runs etcdctl against the local etcd member using the
+// admin certificates generated by Kubespray.
+const kubesprayEtcdctl = "ETCDCTL_API=3 etcdctl" +
+	" --endpoints=https://127.0.0.1:2379" +
+	" --cacert=/etc/ssl/etcd/ssl/ca.pem" +
+	" --cert=/etc/ssl/etcd/ssl/admin-$(hostname).pem" +
+	" --key=/etc/ssl/etcd/ssl/admin-$(hostname)-key.pem"
+
+// Directory with manifests of the control plane static pods and the
+// directory to which they are moved while the control plane is stopped.
+const (
+	kubesprayManifestsDir        = "/etc/kubernetes/manifests"
+	kubesprayStoppedManifestsDir = "/etc/kubernetes/manifests.stopped"
+)
+
+// kubesprayEtcd manages etcd deployed by Kubespray, which runs as a
+// systemd service on each control plane node.
+type kubesprayEtcd struct{}
+
+func (kubesprayEtcd) snapshot(path string) string {
+	return fmt.Sprintf("%s snapshot save %s", kubesprayEtcdctl, exec.ShellQuote(path))
+}
+
+func (kubesprayEtcd) members() string {
+	return fmt.Sprintf("%s member list --write-out=simple | cut -d, -f3 | tr -d ' '", kubesprayEtcdctl)
+}
+
+// stop moves manifests of the control plane static pods away, which makes
+// the kubelet stop the control plane, and stops etcd.
+func (kubesprayEtcd) stop() string {
+	return fmt.Sprintf(`set -e
+test -d %[2]s || mv %[1]s %[2]s
+systemctl stop etcd`, kubesprayManifestsDir, kubesprayStoppedManifestsDir)
+}
+
+// restore restores the snapshot into a new data directory using the
+// member configuration from the etcd environment file. The previous data
+// directory is kept with the suffix ".old".
+func (kubesprayEtcd) restore(path string, first bool) string {
+	return fmt.Sprintf(`set -e
+. /etc/etcd.env
+rm -rf "$ETCD_DATA_DIR.restore"
+ETCDCTL_API=3 etcdctl snapshot restore %s \
+	--name "$ETCD_NAME" \
+	--initial-cluster "$ETCD_INITIAL_CLUSTER" \
+	--initial-cluster-token "$ETCD_INITIAL_CLUSTER_TOKEN" \
+	--initial-advertise-peer-urls "$ETCD_INITIAL_ADVERTISE_PEER_URLS" \
+	--data-dir "$ETCD_DATA_DIR.restore"
+chown -R --reference="$ETCD_DATA_DIR" "$ETCD_DATA_DIR.restore"
+rm -rf "$ETCD_DATA_DIR.old"
+mv "$ETCD_DATA_DIR" "$ETCD_DATA_DIR.old"
+mv "$ETCD_DATA_DIR.restore" "$ETCD_DATA_DIR"`, exec.ShellQuote(path))
+}
+
+// start starts etcd without waiting for it, since the member becomes ready
+// only once the quorum of members is started.
+func (kubesprayEtcd) start(first bool) string {
+	return fmt.Sprintf(`set -e
+systemctl start --no-block etcd
+test -d %[1]s || mv %[2]s %[1]s`, kubesprayManifestsDir, kubesprayStoppedManifestsDir)
+}
+
+// k3sDataDir is the directory containing the embedded etcd data of k3s.
+const k3sDataDir = "/var/lib/rancher/k3s/server/db"
+
+// k3sEtcd manages etcd embedded in k3s servers.
+type k3sEtcd struct{}
+
+// snapshot saves the snapshot into a temporary directory, since k3s
+// appends the node name and timestamp to the snapshot name.
+func (k3sEtcd) snapshot(path string) string {
+	return fmt.Sprintf(`set -e
+dir=$(mktemp -d)
+trap 'rm -rf "$dir"' EXIT
+k3s etcd-snapshot save --name kubitect --dir "$dir"
+mv "$dir"/kubitect* %s`, exec.ShellQuote(path))
+}
+
+func (k3sEtcd) members() string {
+	return `k3s kubectl get nodes --selector node-role.kubernetes.io/etcd=true --output jsonpath='{range .items[*]}{.metadata.name}{"\n"}{end}'`
+}
+
+func (k3sEtcd) stop() string {
+	return "systemctl stop k3s"
+}
+
+// restore resets the cluster on the first member to the snapshot. Data
+// of remaining members is moved away, so that they rejoin the restored
+// member once started.
+func (k3sEtcd) restore(path string, first bool) string {
+	if first {
+		return fmt.Sprintf("k3s server --cluster-reset --cluster-reset-restore-path=%s", exec.ShellQuote(path))
+	}
+
+	return fmt.Sprintf(`set -e
+rm -rf %[1]s.old
+mv %[1]s %[1]s.old`, k3sDataDir)
+}
+
+func (k3sEtcd) start(first bool) string {
+	return "systemctl start k3s"
+}
@@ -0,0 +1,268 @@
+package event
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+	"github.com/hashicorp/go-version"
+)
+
+// ConditionValue selects the value of a change that a condition is
+// evaluated on.
+type ConditionValue string
+
+const (
+	// ValueBefore is the value before the change (applied value).
+	ValueBefore ConditionValue = "before"
+
+	// ValueAfter is the value after the change (new value).
+	ValueAfter ConditionValue = "after"
+)
+
+// ConditionOperator determines how a value of a change is compared with the
+// operand of a condition.
+type ConditionOperator string
+
+const (
+	// Equal and NotEqual compare values as strings.
+	Equal    ConditionOperator = "eq"
+	NotEqual ConditionOperator = "ne"
+
+	// Numeric comparison operators.
+	GreaterThan    ConditionOperator = "gt"
+	GreaterOrEqual ConditionOperator = "ge"
+	LessThan       ConditionOperator = "lt"
+	LessOrEqual    ConditionOperator = "le"
+
+	// Semantic version comparison operators.
+	VersionGreaterThan    ConditionOperator = "versionGt"
+	VersionGreaterOrEqual ConditionOperator = "versionGe"
+	VersionLessThan       ConditionOperator = "versionLt"
+	VersionLessOrEqual    ConditionOperator = "versionLe"
+
+	// SameMajor and SameMinor check whether semantic versions share
+	// the major, or the major and minor version respectively.
+	SameMajor ConditionOperator = "sameMajor"
+	SameMinor ConditionOperator = "sameMinor"
+
+	// Matches checks whether a value matches a regular expression.
+	Matches ConditionOperator = "matches"
+)
+
+var numericOperators = []ConditionOperator{GreaterThan, GreaterOrEqual, LessThan, LessOrEqual}
+
+var versionOperators = []ConditionOperator{VersionGreaterThan, VersionGreaterOrEqual, VersionLessThan, VersionLessOrEqual, SameMajor, SameMinor}
+
+var conditionOperators = slices.Concat([]ConditionOperator{Equal, NotEqual, Matches}, numericOperators, versionOperators)
+
+// Condition is a predicate over values of a change. A value of the change
+// is compared either with a literal operand or with the other value of the
+// same change, which allows distinguishing, for example, an increase from
+// a decrease.
+//
+// A condition is not satisfied if the compared value does not exist (for
+// example, the value before the change of a created field) or cannot be
+// interpreted as required by the operator.
+type Condition struct {
+	// Value is the compared value of the change. If omitted, the value
+	// after the change is used.
+	Value ConditionValue
+
+	Operator ConditionOperator
+
+	// Operand is a literal the value is compared with.
+	Operand string
+
+	// CompareWith compares the value with the other value of the same
+	// change instead of the literal operand.
+	CompareWith ConditionValue
+
+	// Not negates the result of the condition.
+	Not bool
+}
+
+func (c Condition) String() string {
+	operand := strconv.Quote(c.Operand)
+	if c.CompareWith != "" {
+		operand = string(c.CompareWith)
+	}
+
+	s := fmt.Sprintf("%s %s %s", c.value(), c.Operator, operand)
+	if c.Not {
+		s = "not " + s
+	}
+
+	return s
+}
+
+// Validate ensures the condition's operator, values and operand are valid.
+func (c Condition) Validate() error {
+	if !SliceContains(conditionOperators, c.Operator) {
+		return NewValidationError(c, "Invalid operator %q. Valid operators are: %v", c.Operator, conditionOperators)
+	}
+
+	values := []ConditionValue{ValueBefore, ValueAfter}
+
+	if !SliceContains(values, c.value()) {
+		return NewValidationError(c, "Invalid value %q. Valid values are: %v", c.Value, values)
+	}
+
+	if c.CompareWith != "" {
+		if !SliceContains(values, c.CompareWith) {
+			return NewValidationError(c, "Invalid compared value %q. Valid values are: %v", c.CompareWith, values)
+		}
+
+		if c.Operator == Matches {
+			return NewValidationError(c, "Operator %q requires a literal operand", Matches)
+		}
+
+		if c.Operand != "" {
+			return NewValidationError(c, "Operand and compared value are mutually exclusive")
+		}
+
+		return nil
+	}
+
+	var err error
+	switch {
+	case c.Operator == Matches:
+		_, err = regexp.Compile(c.Operand)
+	case SliceContains(numericOperators, c.Operator):
+		_, err = strconv.ParseFloat(c.Operand, 64)
+	case SliceContains(versionOperators, c.Operator):
+		_, err = version.NewVersion(c.Operand)
+	}
+
+	if err != nil {
+		return NewValidationError(c, "Invalid operand %q for operator %q: %v", c.Operand, c.Operator, err)
+	}
+
+	return nil
+}
+
+// Evaluate evaluates the condition on the given change.
+func (c Condition) Evaluate(change cmp.Change) bool {
+	value, ok := changeValue(change, c.value())
+	if !ok {
+		return false
+	}
+
+	operand := c.Operand
+	if c.CompareWith != "" {
+		operand, ok = changeValue(change, c.CompareWith)
+		if !ok {
+			return false
+		}
+	}
+
+	res, ok := c.compare(value, operand)
+	if !ok {
+		return false
+	}
+
+	return res != c.Not
+}
+
+// compare compares the value with the operand according to the condition's
+// operator. The second return value is false if the value or the operand
+// cannot be interpreted as required by the operator.
+func (c Condition) compare(value, operand string) (bool, bool) {
+	switch {
+	case c.Operator == Equal:
+		return value == operand, true
+
+	case c.Operator == NotEqual:
+		return value != operand, true
+
+	case c.Operator == Matches:
+		re, err := regexp.Compile(operand)
+		if err != nil {
+			return false, false
+		}
+
+		return re.MatchString(value), true
+
+	case SliceContains(numericOperators, c.Operator):
+		v, err1 := strconv.ParseFloat(value, 64)
+		o, err2 := strconv.ParseFloat(operand, 64)
+		if err1 != nil || err2 != nil {
+			return false, false
+		}
+
+		switch c.Operator {
+		case GreaterThan:
+			return v > o, true
+		case GreaterOrEqual:
+			return v >= o, true
+		case LessThan:
+			return v < o, true
+		default:
+			return v <= o, true
+		}
+
+	case SliceContains(versionOperators, c.Operator):
+		v, err1 := version.NewVersion(value)
+		o, err2 := version.NewVersion(operand)
+		if err1 != nil || err2 != nil {
+			return false, false
+		}
+
+		vSeg := v.Segments()
+		oSeg := o.Segments()
+
+		switch c.Operator {
+		case VersionGreaterThan:
+			return v.GreaterThan(o), true
+		case VersionGreaterOrEqual:
+			return v.GreaterThanOrEqual(o), true
+		case VersionLessThan:
+			return v.LessThan(o), true
+		case VersionLessOrEqual:
+			return v.LessThanOrEqual(o), true
+		case SameMajor:
+			return vSeg[0] == oSeg[0], true
+		default:
+			return vSeg[0] == oSeg[0] && vSeg[1] == oSeg[1], true
+		}
+	}
+
+	return false, false
+}
+
+// value returns the compared value of the change, which defaults to the
+// value after the change.
+func (c Condition) value() ConditionValue {
+	if c.Value == "" {
+		return ValueAfter
+	}
+
+	return c.Value
+}
+
+// changeValue returns the selected value of the change as a string. Pointers
+// are dereferenced, and false is returned if the value does not exist.
+func changeValue(change cmp.Change, v ConditionValue) (string, bool) {
+	value := change.ValueAfter
+	if v == ValueBefore {
+		value = change.ValueBefore
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "", false
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return "", false
+	}
+
+	return fmt.Sprint(rv.Interface()), true
+}
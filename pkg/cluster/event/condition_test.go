@@ -0,0 +1,85 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCondition_Validate(t *testing.T) {
+	tests := []struct {
+		Condition Condition
+		IsValid   bool
+	}{
+		// Valid conditions.
+		{IsValid: true, Condition: Condition{Operator: Equal, Operand: "a"}},
+		{IsValid: true, Condition: Condition{Operator: Equal}},
+		{IsValid: true, Condition: Condition{Value: ValueBefore, Operator: GreaterThan, Operand: "1.5"}},
+		{IsValid: true, Condition: Condition{Operator: LessThan, CompareWith: ValueBefore}},
+		{IsValid: true, Condition: Condition{Operator: VersionLessThan, Operand: "v1.28.0"}},
+		{IsValid: true, Condition: Condition{Operator: SameMinor, CompareWith: ValueBefore, Not: true}},
+		{IsValid: true, Condition: Condition{Operator: Matches, Operand: "^v1\\.2[0-9]\\."}},
+		// Invalid conditions.
+		{IsValid: false, Condition: Condition{}},
+		{IsValid: false, Condition: Condition{Operator: "contains", Operand: "a"}},
+		{IsValid: false, Condition: Condition{Value: "now", Operator: Equal}},
+		{IsValid: false, Condition: Condition{Operator: Equal, CompareWith: "now"}},
+		{IsValid: false, Condition: Condition{Operator: Equal, Operand: "a", CompareWith: ValueBefore}},
+		{IsValid: false, Condition: Condition{Operator: GreaterThan, Operand: "a"}},
+		{IsValid: false, Condition: Condition{Operator: VersionGreaterThan, Operand: "latest"}},
+		{IsValid: false, Condition: Condition{Operator: Matches, Operand: "("}},
+		{IsValid: false, Condition: Condition{Operator: Matches, CompareWith: ValueBefore}},
+	}
+
+	for _, test := range tests {
+		err := test.Condition.Validate()
+
+		if test.IsValid {
+			assert.NoError(t, err, "Condition %q should be valid!", test.Condition)
+		} else {
+			assert.Error(t, err, "Condition %q should NOT be valid!", test.Condition)
+		}
+	}
+}
+
+func TestCondition_Evaluate(t *testing.T) {
+	ram := 4
+
+	tests := []struct {
+		Condition Condition
+		Before    any
+		After     any
+		Expect    bool
+	}{
+		{Condition: Condition{Operator: Equal, Operand: "true"}, After: "true", Expect: true},
+		{Condition: Condition{Operator: Equal, Operand: "true"}, After: "false", Expect: false},
+		{Condition: Condition{Operator: NotEqual, Operand: "true"}, After: "false", Expect: true},
+		{Condition: Condition{Value: ValueBefore, Operator: Equal, Operand: "true"}, Before: "true", Expect: true},
+		{Condition: Condition{Operator: GreaterThan, Operand: "2"}, After: &ram, Expect: true},
+		{Condition: Condition{Operator: LessOrEqual, Operand: "4"}, After: 4, Expect: true},
+		{Condition: Condition{Operator: LessThan, CompareWith: ValueBefore}, Before: 8, After: 4, Expect: true},
+		{Condition: Condition{Operator: LessThan, CompareWith: ValueBefore}, Before: 4, After: 8, Expect: false},
+		{Condition: Condition{Operator: GreaterOrEqual, CompareWith: ValueBefore}, Before: 4, After: 4, Expect: true},
+		{Condition: Condition{Operator: VersionLessThan, CompareWith: ValueBefore}, Before: "v1.28.6", After: "v1.28.5", Expect: true},
+		{Condition: Condition{Operator: VersionGreaterThan, Operand: "v1.27"}, After: "v1.28.0", Expect: true},
+		{Condition: Condition{Operator: SameMinor, CompareWith: ValueBefore}, Before: "v1.28.6", After: "v1.28.7", Expect: true},
+		{Condition: Condition{Operator: SameMinor, CompareWith: ValueBefore}, Before: "v1.28.6", After: "v1.29.0", Expect: false},
+		{Condition: Condition{Operator: SameMinor, CompareWith: ValueBefore, Not: true}, Before: "v1.28.6", After: "v1.29.0", Expect: true},
+		{Condition: Condition{Operator: SameMajor, CompareWith: ValueBefore}, Before: "v1.28.6", After: "v1.29.0", Expect: true},
+		{Condition: Condition{Operator: Matches, Operand: "^worker-"}, After: "worker-1", Expect: true},
+		{Condition: Condition{Operator: Matches, Operand: "^worker-"}, After: "master-1", Expect: false},
+		// Missing values never satisfy a condition (even if negated).
+		{Condition: Condition{Operator: Equal, Operand: ""}, After: nil, Expect: false},
+		{Condition: Condition{Operator: LessThan, CompareWith: ValueBefore, Not: true}, After: 4, Expect: false},
+		{Condition: Condition{Operator: Equal, Operand: "4"}, After: (*int)(nil), Expect: false},
+		// Values that cannot be interpreted never satisfy a condition.
+		{Condition: Condition{Operator: GreaterThan, Operand: "2", Not: true}, After: "a", Expect: false},
+		{Condition: Condition{Operator: VersionGreaterThan, Operand: "v1.0", Not: true}, After: "latest", Expect: false},
+	}
+
+	for _, test := range tests {
+		change := cmp.Change{ValueBefore: test.Before, ValueAfter: test.After}
+		assert.Equal(t, test.Expect, test.Condition.Evaluate(change), "Condition %q on %v -> %v", test.Condition, test.Before, test.After)
+	}
+}
@@ -17,6 +17,8 @@ func NewValidationError(o any, errFmt string, errArgs ...any) ValidationError {
 		return ValidationError{fmt.Sprintf("Rule path segment %q: %s", v.path, errMsg)}
 	case RulePath:
 		return ValidationError{fmt.Sprintf("Rule path %q: %s", v.path, errMsg)}
+	case Condition:
+		return ValidationError{fmt.Sprintf("Condition %q: %s", v.String(), errMsg)}
 	case Rule:
 		return ValidationError{fmt.Sprintf("Rule %q: %s", v.MatchPath.path, errMsg)}
 	default:
@@ -56,6 +56,38 @@ func (events Events) FilterByAction(t ActionType) Events {
 	return events.Filter(filter)
 }
 
+// Summary is a serializable representation of an event.
+type Summary struct {
+	RuleType           string         `json:"ruleType" yaml:"ruleType"`
+	ActionType         ActionType     `json:"actionType,omitempty" yaml:"actionType,omitempty"`
+	ChangeType         cmp.ChangeType `json:"changeType" yaml:"changeType"`
+	Path               string         `json:"path" yaml:"path"`
+	MatchedChangePaths []string       `json:"matchedChangePaths,omitempty" yaml:"matchedChangePaths,omitempty"`
+	Message            string         `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Summary returns a serializable representation of the event.
+func (e Event) Summary() Summary {
+	return Summary{
+		RuleType:           e.Rule.Type.String(),
+		ActionType:         e.Rule.ActionType,
+		ChangeType:         e.Change.Type,
+		Path:               e.Change.Path,
+		MatchedChangePaths: e.MatchedChangePaths,
+		Message:            e.Rule.Message,
+	}
+}
+
+// Summaries returns serializable representations of the events.
+func (events Events) Summaries() []Summary {
+	summaries := make([]Summary, 0, len(events))
+	for _, e := range events {
+		summaries = append(summaries, e.Summary())
+	}
+
+	return summaries
+}
+
 // GenerateEvents evaluates the changes from the comparison tree against the
 // provided rules and returns a list of corresponding events. Each event
 // encapsulates a matched change and its associated rule. A single change can
@@ -125,8 +157,9 @@ func createAndAddEvent(node *cmp.DiffNode, rule *Rule, events []Event) []Event {
 			isSameRulePath := e.Rule.MatchPath.Path() == event.Rule.MatchPath.Path()
 			isSameRuleType := e.Rule.Type == event.Rule.Type
 			isSameChangeType := e.Change.Type == event.Change.Type
+			isSameChangePath := e.Change.Path == event.Change.Path
 
-			if isSameRuleType && isSameChangeType && isSameRulePath {
+			if isSameRuleType && isSameChangeType && isSameChangePath && isSameRulePath {
 				events[i].MatchedChangePaths = append(events[i].MatchedChangePaths, node.Path())
 				return events
 			}
@@ -139,41 +172,103 @@ func createAndAddEvent(node *cmp.DiffNode, rule *Rule, events []Event) []Event {
 }
 
 // matchRule determines the most appropriate rule for a given change. A change
-// matches a rule if both the path and change type align. Each change can
+// matches a rule if both the path and change type align, and the change
+// satisfies all rule's conditions. Each change can
 // correspond to either no rule or one rule. If multiple rules match, they are
 // prioritized by path length, wildcard count, and rule priority. If no rule
 // matches, it returns nil.
+//
+// Built-in and policy rules are matched separately. The best matching policy
+// rule is selected only if it has a higher priority than the best matching
+// built-in rule.
 func matchRule(node *cmp.DiffNode, rules []Rule) *Rule {
+	bestMatch := matchRuleOf(node, rules, false)
+	policyMatch := matchRuleOf(node, rules, true)
+
+	if policyMatch == nil {
+		return bestMatch
+	}
+
+	if bestMatch == nil || policyMatch.Type.Normalize() > bestMatch.Type.Normalize() {
+		return policyMatch
+	}
+
+	return bestMatch
+}
+
+// matchRuleOf returns the best matching rule for a given change among either
+// built-in or policy rules.
+func matchRuleOf(node *cmp.DiffNode, rules []Rule, policy bool) *Rule {
 	var bestMatch *Rule
 	for i, rule := range rules {
-		if rule.MatchChangeType != node.ChangeType() && rule.MatchChangeType != cmp.Any {
+		if rule.Policy != policy {
 			continue
 		}
 
-		if rule.MatchPath.Matches(node.Path()) {
-			if bestMatch == nil || isBetterMatch(rule, *bestMatch) {
-				bestMatch = &rules[i]
-			}
+		if ruleMatches(node, rule) && (bestMatch == nil || isBetterMatch(rule, *bestMatch)) {
+			bestMatch = &rules[i]
 		}
-
 	}
 
 	return bestMatch
 }
 
+// ruleMatches checks whether the rule matches a given change, regardless
+// of other rules.
+func ruleMatches(node *cmp.DiffNode, rule Rule) bool {
+	if !rule.MatchPath.Matches(node.Path()) {
+		return false
+	}
+
+	changeType := node.ChangeType()
+	if rule.MatchAnchorChangeType && rule.MatchPath.IsAnchorPath() {
+		anchorNode := node.ParentByPath(rule.MatchPath.FindAnchorPath(node.Path()))
+		if anchorNode != nil {
+			changeType = anchorNode.ChangeType()
+		}
+	}
+
+	if rule.MatchChangeType != changeType && rule.MatchChangeType != cmp.Any {
+		return false
+	}
+
+	return rule.MatchesConditions(node.ToChange())
+}
+
+// matchCriterion is a criterion by which one of the matching rules is
+// preferred over another.
+type matchCriterion string
+
+const (
+	criterionPathLength matchCriterion = "path length"
+	criterionWildcards  matchCriterion = "wildcard count"
+	criterionConditions matchCriterion = "condition count"
+	criterionPriority   matchCriterion = "priority"
+)
+
 // isBetterMatch determines whether rule r1 is a better match then rule r2
 // based on the following rules:
 //   1. Path Length: Longer paths are prioritized.
 //   2. Wildcard Count: Paths with fewer wildcards are deemed more specific
 //	and are prioritized.
-//   3. Rule Priority: Higher priority rules are prioritized.
+//   3. Conditions: Rules with more conditions are deemed more specific
+//	and are prioritized.
+//   4. Rule Priority: Higher priority rules are prioritized.
 func isBetterMatch(r1, r2 Rule) bool {
+	_, better := compareMatch(r1, r2)
+	return better
+}
+
+// compareMatch returns the criterion that decides between rules r1 and r2,
+// and whether rule r1 is a better match by that criterion. If rules are
+// equal by all criteria, an empty criterion is returned.
+func compareMatch(r1, r2 Rule) (matchCriterion, bool) {
 	r1Len := r1.MatchPath.Len()
 	r2Len := r2.MatchPath.Len()
 
 	// Longer path has precedence.
 	if r1Len != r2Len {
-		return r1Len > r2Len
+		return criterionPathLength, r1Len > r2Len
 	}
 
 	r1Wcs := r1.MatchPath.WildcardCount()
@@ -181,9 +276,21 @@ func isBetterMatch(r1, r2 Rule) bool {
 
 	// Path with fewer wildcards has precedence.
 	if r1Wcs != r2Wcs {
-		return r1Wcs < r2Wcs
+		return criterionWildcards, r1Wcs < r2Wcs
+	}
+
+	r1Conds := len(r1.MatchConditions)
+	r2Conds := len(r2.MatchConditions)
+
+	// Rule with more conditions has precedence.
+	if r1Conds != r2Conds {
+		return criterionConditions, r1Conds > r2Conds
 	}
 
 	// Higher priority has precedence.
-	return r1.Type > r2.Type
+	if r1.Type != r2.Type {
+		return criterionPriority, r1.Type > r2.Type
+	}
+
+	return "", false
 }
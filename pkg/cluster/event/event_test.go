@@ -132,6 +132,70 @@ func TestEvent_RulePrecedenceByPriority_Custom(t *testing.T) {
 	assert.Equal(t, r2, events[0].Rule)
 }
 
+// Test expects a policy rule to take precedence over a more specific
+// built-in rule only if its priority is higher.
+func TestEvent_RulePrecedencePolicy(t *testing.T) {
+	type Map map[string]any
+
+	v1 := map[string]Map{"a": {"b": "Yes"}}
+	v2 := map[string]Map{"a": {"b": "No"}}
+
+	builtin := Rule{Type: Warn, MatchPath: NewRulePath("a.b")}
+	stricter := Rule{Type: Error, MatchPath: NewRulePath("a"), Policy: true}
+	looser := Rule{Type: Allow, MatchPath: NewRulePath("a.b"), Policy: true}
+
+	events := mustGenEvents(t, v1, v2, []Rule{builtin, stricter})
+	require.Len(t, events, 1)
+	assert.Equal(t, stricter, events[0].Rule)
+
+	events = mustGenEvents(t, v1, v2, []Rule{builtin, looser})
+	require.Len(t, events, 1)
+	assert.Equal(t, builtin, events[0].Rule)
+
+	// Policy rule applies to changes not matched by built-in rules.
+	events = mustGenEvents(t, v1, v2, []Rule{looser})
+	require.Len(t, events, 1)
+	assert.Equal(t, looser, events[0].Rule)
+}
+
+// Test expects a rule to match only changes that satisfy its conditions,
+// and a conditional rule to take precedence over an unconditional one.
+func TestEvent_RuleConditions(t *testing.T) {
+	v1 := map[string]int{"a": 4}
+	v2 := map[string]int{"a": 8}
+
+	grow := Rule{Type: Allow, MatchPath: NewRulePath("a")}
+	shrink := Rule{
+		Type:      Error,
+		MatchPath: NewRulePath("a"),
+		MatchConditions: []Condition{
+			{Operator: LessThan, CompareWith: ValueBefore},
+		},
+	}
+
+	events := mustGenEvents(t, v1, v2, []Rule{grow, shrink})
+	require.Len(t, events, 1)
+	assert.Equal(t, grow, events[0].Rule)
+
+	events = mustGenEvents(t, v2, v1, []Rule{grow, shrink})
+	require.Len(t, events, 1)
+	assert.Equal(t, shrink, events[0].Rule)
+
+	// Change that does not satisfy conditions is not matched.
+	events = mustGenEvents(t, v1, v2, []Rule{shrink})
+	require.Len(t, events, 0)
+}
+
+func TestEvent_RuleConditions_Invalid(t *testing.T) {
+	r := Rule{
+		MatchPath:       NewRulePath("a"),
+		MatchConditions: []Condition{{Operator: GreaterThan, Operand: "a"}},
+	}
+
+	_, err := GenerateEvents(nil, []Rule{r})
+	assert.ErrorContains(t, err, `Invalid operand "a"`)
+}
+
 func TestEvent_RulePathWildcard(t *testing.T) {
 	v1 := map[string]map[string]string{"A": {"a": "Yes"}}
 	v2 := map[string]map[string]string{"A": {"a": "No"}}
@@ -152,6 +216,46 @@ func TestEvent_RulePathWildcard(t *testing.T) {
 	assert.Equal(t, "A.a", events[0].MatchedChangePaths[0])
 }
 
+// Test expects changes of different anchor nodes to produce separate events.
+func TestEvent_RulePathAnchor_Grouping(t *testing.T) {
+	v1 := map[string]map[string]string{"A": {"a": "1", "b": "1"}, "B": {"a": "1"}}
+	v2 := map[string]map[string]string{"A": {"a": "2", "b": "2"}, "B": {"a": "2"}}
+
+	r := Rule{MatchPath: NewRulePath("@")}
+
+	events := mustGenEvents(t, v1, v2, []Rule{r})
+	require.Len(t, events, 2)
+
+	paths := map[string]int{}
+	for _, e := range events {
+		paths[e.Change.Path] = len(e.MatchedChangePaths)
+	}
+
+	assert.Equal(t, map[string]int{"A": 2, "B": 1}, paths)
+}
+
+// Test expects a rule matching the anchor change type to ignore deleted
+// leaves of a node that has not been deleted.
+func TestEvent_RulePathAnchor_ChangeType(t *testing.T) {
+	v1 := map[string]map[string]string{"A": {"a": "1", "b": "1"}, "B": {"a": "1", "b": "1"}}
+	v2 := map[string]map[string]string{"A": {"b": "1"}}
+
+	r := Rule{
+		MatchPath:       NewRulePath("@.a"),
+		MatchChangeType: cmp.Delete,
+	}
+
+	events := mustGenEvents(t, v1, v2, []Rule{r})
+	assert.Len(t, events, 2)
+
+	r.MatchAnchorChangeType = true
+
+	events = mustGenEvents(t, v1, v2, []Rule{r})
+	require.Len(t, events, 1)
+	assert.Equal(t, "B", events[0].Change.Path)
+	assert.Equal(t, cmp.Delete, events[0].Change.Type)
+}
+
 func TestEvent_RulePathOption(t *testing.T) {
 	v1 := map[string]map[string]string{"A": {"a": "Yes"}}
 	v2 := map[string]map[string]string{"A": {"b": "No"}, "B": {"c": ""}}
@@ -0,0 +1,190 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+)
+
+// Explanation describes how a rule has been selected for a single change.
+// It lists all rules that match the change, along with the reason why each
+// of them has been selected or rejected.
+type Explanation struct {
+	Change     cmp.Change
+	Candidates []Candidate
+
+	// Rule is the selected rule or nil if no rule matches the change.
+	Rule *Rule
+}
+
+// Candidate is a rule that matches the change.
+type Candidate struct {
+	Rule     Rule
+	Selected bool
+	Reason   string
+}
+
+// ExplainEvents evaluates the changes from the comparison tree against the
+// provided rules the same way as GenerateEvents, but instead of events it
+// returns an explanation of rule matching for each changed leaf.
+// Note that provided rules are validated prior the evaluation.
+func ExplainEvents(node *cmp.DiffNode, rules []Rule) ([]Explanation, error) {
+	for _, r := range rules {
+		err := r.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return explainEvents(node, rules, []Explanation{}), nil
+}
+
+func explainEvents(node *cmp.DiffNode, rules []Rule, expls []Explanation) []Explanation {
+	if node == nil {
+		return expls
+	}
+
+	if node.IsLeaf() && node.HasChanged() {
+		expls = append(expls, explain(node, rules))
+	}
+
+	for _, c := range node.Children() {
+		expls = explainEvents(c, rules, expls)
+	}
+
+	return expls
+}
+
+// explain explains the rule matching for a given change. The selected rule
+// is listed first, followed by other matching rules in the order in which
+// they are defined.
+func explain(node *cmp.DiffNode, rules []Rule) Explanation {
+	expl := Explanation{
+		Change: node.ToChange(),
+		Rule:   matchRule(node, rules),
+	}
+
+	if expl.Rule == nil {
+		return expl
+	}
+
+	bestMatch := matchRuleOf(node, rules, false)
+	policyMatch := matchRuleOf(node, rules, true)
+
+	var candidates []Candidate
+	for i, r := range rules {
+		if !ruleMatches(node, r) {
+			continue
+		}
+
+		if &rules[i] == expl.Rule {
+			expl.Candidates = append(expl.Candidates, Candidate{
+				Rule:     r,
+				Selected: true,
+				Reason:   "best match",
+			})
+
+			continue
+		}
+
+		kindMatch := bestMatch
+		if r.Policy {
+			kindMatch = policyMatch
+		}
+
+		var reason string
+		if &rules[i] == kindMatch {
+			reason = rejectedByKind(r, *expl.Rule)
+		} else {
+			reason = rejectedBy(r, *kindMatch)
+		}
+
+		candidates = append(candidates, Candidate{Rule: r, Reason: reason})
+	}
+
+	expl.Candidates = append(expl.Candidates, candidates...)
+
+	return expl
+}
+
+// rejectedBy returns the reason why the rule has been rejected in favor of
+// a better matching rule of the same kind (built-in or policy).
+func rejectedBy(r Rule, best Rule) string {
+	criterion, _ := compareMatch(best, r)
+
+	switch criterion {
+	case criterionPathLength:
+		return fmt.Sprintf("shorter path (%d < %d segments)", r.MatchPath.Len(), best.MatchPath.Len())
+	case criterionWildcards:
+		return fmt.Sprintf("more wildcards (%d > %d)", r.MatchPath.WildcardCount(), best.MatchPath.WildcardCount())
+	case criterionConditions:
+		return fmt.Sprintf("fewer conditions (%d < %d)", len(r.MatchConditions), len(best.MatchConditions))
+	case criterionPriority:
+		return fmt.Sprintf("lower priority (%s < %s)", r.Type, best.Type)
+	default:
+		return "same precedence as a preceding rule"
+	}
+}
+
+// rejectedByKind returns the reason why the best matching rule of one kind
+// has been rejected in favor of the best matching rule of the other kind.
+func rejectedByKind(r Rule, selected Rule) string {
+	if r.Policy {
+		return fmt.Sprintf("policy rule does not increase priority of the built-in rule (%s <= %s)", r.Type, selected.Type)
+	}
+
+	return fmt.Sprintf("overridden by a policy rule with higher priority (%s < %s)", r.Type, selected.Type)
+}
+
+// ExplanationSummary is a serializable representation of an explanation.
+type ExplanationSummary struct {
+	ChangeType cmp.ChangeType     `json:"changeType" yaml:"changeType"`
+	Path       string             `json:"path" yaml:"path"`
+	RuleType   string             `json:"ruleType,omitempty" yaml:"ruleType,omitempty"`
+	ActionType ActionType         `json:"actionType,omitempty" yaml:"actionType,omitempty"`
+	Candidates []CandidateSummary `json:"candidates" yaml:"candidates"`
+}
+
+// CandidateSummary is a serializable representation of a candidate rule.
+type CandidateSummary struct {
+	RuleType   string     `json:"ruleType" yaml:"ruleType"`
+	RulePath   string     `json:"rulePath" yaml:"rulePath"`
+	ChangeType string     `json:"changeType" yaml:"changeType"`
+	ActionType ActionType `json:"actionType,omitempty" yaml:"actionType,omitempty"`
+	Policy     bool       `json:"policy" yaml:"policy"`
+	Selected   bool       `json:"selected" yaml:"selected"`
+	Reason     string     `json:"reason" yaml:"reason"`
+}
+
+// Summary returns a serializable representation of the explanation.
+func (e Explanation) Summary() ExplanationSummary {
+	s := ExplanationSummary{
+		ChangeType: e.Change.Type,
+		Path:       e.Change.Path,
+		Candidates: make([]CandidateSummary, 0, len(e.Candidates)),
+	}
+
+	if e.Rule != nil {
+		s.RuleType = e.Rule.Type.String()
+		s.ActionType = e.Rule.ActionType
+	}
+
+	for _, c := range e.Candidates {
+		changeType := string(c.Rule.MatchChangeType)
+		if c.Rule.MatchChangeType == cmp.Any {
+			changeType = "any"
+		}
+
+		s.Candidates = append(s.Candidates, CandidateSummary{
+			RuleType:   c.Rule.Type.String(),
+			RulePath:   c.Rule.MatchPath.Path(),
+			ChangeType: changeType,
+			ActionType: c.Rule.ActionType,
+			Policy:     c.Rule.Policy,
+			Selected:   c.Selected,
+			Reason:     c.Reason,
+		})
+	}
+
+	return s
+}
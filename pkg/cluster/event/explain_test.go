@@ -0,0 +1,92 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustExplain(t *testing.T, a any, b any, rules []Rule) []Explanation {
+	expls, err := ExplainEvents(mustCompare(t, a, b).Tree(), rules)
+	require.NoError(t, err)
+	return expls
+}
+
+func TestExplain(t *testing.T) {
+	type Map map[string]any
+
+	v1 := map[string]Map{"a": {"b": "Yes", "c": "Yes"}}
+	v2 := map[string]Map{"a": {"b": "No", "c": "No"}}
+
+	rules := []Rule{
+		{Type: Error, MatchPath: NewRulePath("a")},
+		{Type: Allow, MatchPath: NewRulePath("a.b"), ActionType: Action_Resize},
+		{Type: Warn, MatchPath: NewRulePath("a.*")},
+		{Type: Allow, MatchPath: NewRulePath("a.{b,c}")},
+		{Type: Warn, MatchPath: NewRulePath("a.c")},
+	}
+
+	expls := map[string]Explanation{}
+	for _, e := range mustExplain(t, v1, v2, rules) {
+		expls[e.Change.Path] = e
+	}
+
+	require.Len(t, expls, 2)
+
+	e := expls["a.b"]
+	require.NotNil(t, e.Rule)
+	assert.Equal(t, rules[1], *e.Rule)
+
+	reasons := []string{}
+	for _, c := range e.Candidates {
+		reasons = append(reasons, c.Reason)
+	}
+
+	assert.Equal(t, []string{
+		"best match",
+		"shorter path (1 < 2 segments)",
+		"more wildcards (1 > 0)",
+		"same precedence as a preceding rule",
+	}, reasons)
+
+	assert.True(t, e.Candidates[0].Selected)
+	assert.Equal(t, Action_Resize, e.Summary().ActionType)
+
+	// Rules with the same specificity are distinguished by priority.
+	e = expls["a.c"]
+	require.Len(t, e.Candidates, 4)
+	assert.Equal(t, rules[4], e.Candidates[0].Rule)
+	assert.Equal(t, "lower priority (Allow < Warn)", e.Candidates[3].Reason)
+}
+
+func TestExplain_Policy(t *testing.T) {
+	v1 := map[string]string{"a": "Yes"}
+	v2 := map[string]string{"a": "No"}
+
+	builtin := Rule{Type: Warn, MatchPath: NewRulePath("a")}
+	stricter := Rule{Type: Error, MatchPath: NewRulePath("a"), Policy: true}
+	looser := Rule{Type: Allow, MatchPath: NewRulePath("a"), Policy: true}
+
+	expls := mustExplain(t, v1, v2, []Rule{builtin, stricter})
+	require.Len(t, expls, 1)
+	require.Len(t, expls[0].Candidates, 2)
+	assert.Equal(t, stricter, expls[0].Candidates[0].Rule)
+	assert.Equal(t, "overridden by a policy rule with higher priority (Warn < Error)", expls[0].Candidates[1].Reason)
+
+	expls = mustExplain(t, v1, v2, []Rule{builtin, looser})
+	require.Len(t, expls, 1)
+	require.Len(t, expls[0].Candidates, 2)
+	assert.Equal(t, builtin, expls[0].Candidates[0].Rule)
+	assert.Equal(t, "policy rule does not increase priority of the built-in rule (Allow <= Warn)", expls[0].Candidates[1].Reason)
+}
+
+func TestExplain_NoMatch(t *testing.T) {
+	v1 := map[string]string{"a": "Yes"}
+	v2 := map[string]string{"a": "No"}
+
+	expls := mustExplain(t, v1, v2, []Rule{{MatchPath: NewRulePath("b")}})
+	require.Len(t, expls, 1)
+	assert.Nil(t, expls[0].Rule)
+	assert.Empty(t, expls[0].Candidates)
+}
@@ -104,6 +104,7 @@ type ActionType string
 const (
 	Action_ScaleUp   ActionType = "scale_up"
 	Action_ScaleDown ActionType = "scale_down"
+	Action_Resize    ActionType = "resize"
 )
 
 // Rule defines the conditions that trigger events based on the detected
@@ -116,9 +117,25 @@ type Rule struct {
 	MatchPath       RulePath
 	MatchChangeType cmp.ChangeType
 
+	// MatchConditions are predicates over values of a change. A change
+	// matches the rule only if all conditions are satisfied.
+	MatchConditions []Condition
+
+	// MatchAnchorChangeType matches the change type against the change
+	// of the anchored node instead of the changed leaf, if the rule path
+	// contains an anchor. For example, removing a label of a node is then
+	// not matched as a deletion of the node.
+	MatchAnchorChangeType bool
+
 	// Optional fields.
 	ActionType ActionType
 	Message    string
+
+	// Policy indicates that the rule is defined by the user. Policy rules
+	// can only make the evaluation of a change stricter, therefore they
+	// take precedence over built-in rules only if they are of a higher
+	// priority.
+	Policy bool
 }
 
 // Validate ensures the rule's match change type and path are valid. An error
@@ -129,9 +146,28 @@ func (r Rule) Validate() error {
 		return NewValidationError(r, "Invalid change type %q. Valid change types are: %v", r.MatchChangeType, validChangeTypes)
 	}
 
+	for _, c := range r.MatchConditions {
+		err := c.Validate()
+		if err != nil {
+			return NewValidationError(r, "%v", err)
+		}
+	}
+
 	return r.MatchPath.Validate()
 }
 
+// MatchesConditions checks whether the change satisfies all rule's
+// conditions.
+func (r Rule) MatchesConditions(change cmp.Change) bool {
+	for _, c := range r.MatchConditions {
+		if !c.Evaluate(change) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // IsOfType checks if the rule's type matches the given RuleTypes after both
 // types are normalized. This is useful for comparing RuleTypes that might not
 // be one of the predefined constants but should be treated as if they were.
@@ -1,25 +1,71 @@
 package event
 
 import (
+	"slices"
+
 	"github.com/MusicDin/kubitect/pkg/utils/cmp"
 )
 
-var UpgradeRules = []Rule{
+var UpgradeRules = slices.Concat(commonRules, upgradeRules, []Rule{
+	// Default rule.
+	{
+		Type:            Error,
+		MatchChangeType: cmp.Any,
+		MatchPath:       NewRulePath("@"),
+		Message:         "Change is not allowed. Upgrade action allows changing only 'kubernetes.version' and 'kubernetes.upgrade'.",
+	},
+})
+
+var ScaleRules = slices.Concat(commonRules, scaleRules, []Rule{
+	// Default rule.
+	{
+		Type:            Error,
+		MatchChangeType: cmp.Any,
+		MatchPath:       NewRulePath("*"),
+		Message:         "Change is not allowed. Scale action allows only addition and removal of master, worker and load balancer nodes.",
+	},
+})
+
+// ScaleUpgradeRules allow scaling the cluster and upgrading its Kubernetes
+// version within a single apply.
+var ScaleUpgradeRules = slices.Concat(commonRules, upgradeRules, scaleRules, []Rule{
+	// Default rule.
+	{
+		Type:            Error,
+		MatchChangeType: cmp.Any,
+		MatchPath:       NewRulePath("*"),
+		Message:         "Change is not allowed. Scale-upgrade action allows only addition and removal of master, worker and load balancer nodes, and changing 'kubernetes.version'.",
+	},
+})
+
+// commonRules contain changes allowed by any action.
+var commonRules = []Rule{
+	// Allow changing the policy file, since it only affects how
+	// the changes are evaluated.
+	{
+		Type:            Allow,
+		MatchChangeType: cmp.Any,
+		MatchPath:       NewRulePath("policyFile"),
+	},
+}
+
+// upgradeRules contain changes allowed by the upgrade action.
+var upgradeRules = []Rule{
 	{
 		Type:            Allow,
 		MatchChangeType: cmp.Modify,
 		MatchPath:       NewRulePath("kubernetes.version"),
 	},
-	// Default rule.
+	// Allow changing the upgrade strategy.
 	{
-		Type:            Error,
+		Type:            Allow,
 		MatchChangeType: cmp.Any,
-		MatchPath:       NewRulePath("@"),
-		Message:         "Change is not allowed. Upgrade action allows changing only 'kubernetes.version'.",
+		MatchPath:       NewRulePath("kubernetes.upgrade"),
 	},
 }
 
-var ScaleRules = []Rule{
+// scaleRules contain changes allowed by the scale action.
+var scaleRules = []Rule{
 	{
 		Type:            Allow,
 		MatchChangeType: cmp.Delete,
@@ -45,16 +91,16 @@ var ScaleRules = []Rule{
 		ActionType:      Action_ScaleUp,
 	},
 	{
-		Type:            Error,
-		MatchChangeType: cmp.Create,
+		Type:            Allow,
+		MatchChangeType: cmp.Delete,
 		MatchPath:       NewRulePath("cluster.nodes.master.instances.@"),
-		Message:         "Currently, control plane cannot be scaled.",
+		ActionType:      Action_ScaleDown,
 	},
 	{
 		Type:            Allow,
-		MatchChangeType: cmp.Delete,
+		MatchChangeType: cmp.Create,
 		MatchPath:       NewRulePath("cluster.nodes.master.instances.@"),
-		Message:         "Currently, control plane cannot be scaled.",
+		ActionType:      Action_ScaleUp,
 	},
 	// Allow addition and deletion of hosts.
 	{
@@ -67,13 +113,6 @@ var ScaleRules = []Rule{
 		MatchChangeType: cmp.Delete,
 		MatchPath:       NewRulePath("hosts.@"),
 	},
-	// Default rule.
-	{
-		Type:            Error,
-		MatchChangeType: cmp.Any,
-		MatchPath:       NewRulePath("*"),
-		Message:         "Change is not allowed. Scale action allows only addition and removal of worker and load balancer nodes.",
-	},
 }
 
 var ModifyRules = []Rule{
@@ -133,18 +172,20 @@ var ModifyRules = []Rule{
 		Message:         "To add new nodes run apply command with '--action scale' flag.",
 	},
 	{
-		// Prevent default cpu, ram and main disk size changes.
-		Type:            Error,
+		// Allow default cpu, ram and main disk size changes. Nodes
+		// inherit default values, therefore affected nodes are resized
+		// by the rule below.
+		Type:            Allow,
 		MatchChangeType: cmp.Any,
 		MatchPath:       NewRulePath("cluster.nodes.{master, worker, loadBalancer}.default.{cpu, ram, mainDiskSize}"),
-		Message:         "Changing any default physical properties of nodes (cpu, ram, mainDiskSize) is not allowed. Such action may render the cluster unusable.",
 	},
 	{
-		// Prevent cpu, ram and main disk size changes.
-		Type:            Error,
+		// Warn about cpu, ram and main disk size changes (will restart the node).
+		Type:            Warn,
 		MatchChangeType: cmp.Modify,
 		MatchPath:       NewRulePath("cluster.nodes.{master, worker, loadBalancer}.instances.@.{cpu, ram, mainDiskSize}"),
-		Message:         "Changing any physical properties of nodes (cpu, ram, mainDiskSize) is not allowed. Such action will recreate the node.",
+		Message:         "Changing physical properties of nodes (cpu, ram, mainDiskSize) will resize the affected nodes one at a time. Each node is drained and restarted in the process.",
+		ActionType:      Action_Resize,
 	},
 	{
 		// Prevent IP and MAC changes.
@@ -193,12 +234,24 @@ var ModifyRules = []Rule{
 		MatchPath:       NewRulePath("kubernetes.version"),
 		Message:         "Changing Kubernetes is allowed only when upgrading the cluster.\nTo upgrade the cluster run apply command with '--action upgrade' flag.",
 	},
+	{
+		// Allow upgrade strategy changes.
+		Type:            Allow,
+		MatchChangeType: cmp.Any,
+		MatchPath:       NewRulePath("kubernetes.upgrade"),
+	},
 	{
 		// Allow addons changes.
 		Type:            Allow,
 		MatchChangeType: cmp.Any,
 		MatchPath:       NewRulePath("addons"),
 	},
+	{
+		// Allow policy file changes.
+		Type:            Allow,
+		MatchChangeType: cmp.Any,
+		MatchPath:       NewRulePath("policyFile"),
+	},
 	{
 		// Default rule.
 		Type:            Error,
@@ -268,6 +268,7 @@ func TestRuleLists(t *testing.T) {
 	rules = append(rules, ModifyRules...)
 	rules = append(rules, ScaleRules...)
 	rules = append(rules, UpgradeRules...)
+	rules = append(rules, ScaleUpgradeRules...)
 
 	for _, r := range rules {
 		err := r.Validate()
@@ -0,0 +1,104 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// EXEC_CONNECTION_FAILED is the exit code of a command that could not be
+// executed on the node, for example, because the node is not reachable.
+// It matches the exit code used by the ssh client in such case.
+const EXEC_CONNECTION_FAILED = 255
+
+// ExecResult is the result of a command executed on a single node.
+type ExecResult struct {
+	Node     string `json:"node" yaml:"node"`
+	ExitCode int    `json:"exitCode" yaml:"exitCode"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ExecResults are results of a command executed on multiple nodes.
+type ExecResults []ExecResult
+
+// ExitCode returns the highest exit code among the results.
+func (rs ExecResults) ExitCode() int {
+	code := 0
+	for _, r := range rs {
+		code = max(code, r.ExitCode)
+	}
+
+	return code
+}
+
+// Exec executes the command on the given nodes over SSH. Commands are
+// executed on at most the given number of nodes at once. Each line of the
+// command's output is prefixed with the name of the node that produced it.
+func (c *Cluster) Exec(ctx context.Context, nodes []config.Instance, command string, concurrency int) ExecResults {
+	width := 0
+	for _, n := range nodes {
+		width = max(width, len(c.nodeName(n)))
+	}
+
+	stdout := exec.NewSyncWriter(ui.Streams().Out().File())
+	stderr := exec.NewSyncWriter(ui.Streams().Err().File())
+
+	results := make(ExecResults, len(nodes))
+	sem := make(chan struct{}, max(concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n config.Instance) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			name := c.nodeName(n)
+			prefix := fmt.Sprintf("%-*s | ", width, name)
+
+			outW := exec.NewPrefixWriter(stdout, prefix)
+			errW := exec.NewPrefixWriter(stderr, prefix)
+
+			results[i] = c.execNode(ctx, n, command, outW, errW)
+			results[i].Node = name
+
+			outW.Flush()
+			errW.Flush()
+		}(i, n)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// execNode executes the command on the given node.
+func (c *Cluster) execNode(ctx context.Context, n config.Instance, command string, stdout, stderr *exec.PrefixWriter) ExecResult {
+	client := newNodeClient(c, string(n.GetIP()))
+	defer client.Close()
+
+	client.SetStdout(stdout)
+	client.SetStderr(stderr)
+
+	// The command is passed to the shell as a single argument, which
+	// allows using pipes and other shell constructs.
+	err := client.RunCtx(ctx, "sh", "-c", exec.ShellQuote(command))
+	if err == nil {
+		return ExecResult{}
+	}
+
+	if code, ok := exec.ExitStatus(err); ok {
+		return ExecResult{ExitCode: code}
+	}
+
+	return ExecResult{
+		ExitCode: EXEC_CONNECTION_FAILED,
+		Error:    err.Error(),
+	}
+}
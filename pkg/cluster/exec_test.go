@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExec(t *testing.T) {
+	c := mockStatusCluster(t)
+	name := c.Name
+
+	mockStatus(t, map[string]nodeClientMock{
+		"10.10.0.10": {outputs: map[string]string{"sh -c 'uptime'": "up 1 day\nload 0.1\n"}},
+		"10.10.0.20": {exitCodes: map[string]int{"sh -c 'uptime'": 3}},
+	}, "")
+
+	nodes := c.SelectNodes([]NodeSelector{{"role", "master"}, {"role", "worker"}})
+	rs := c.Exec(context.Background(), nodes, "uptime", 1)
+
+	assert.Equal(t, ExecResults{
+		{Node: name + "-master-1", ExitCode: 0},
+		{Node: name + "-worker-1", ExitCode: 3},
+	}, rs)
+	assert.Equal(t, 3, rs.ExitCode())
+
+	out := c.Ui().ReadStdout(t)
+	assert.Contains(t, out, name+"-master-1 | up 1 day\n")
+	assert.Contains(t, out, name+"-master-1 | load 0.1\n")
+
+	assert.Contains(t, c.Ui().ReadStderr(t), name+"-worker-1 | sh -c 'uptime': exit 3\n")
+}
+
+func TestExec_Unreachable(t *testing.T) {
+	c := mockStatusCluster(t)
+	name := c.Name
+
+	mockStatus(t, map[string]nodeClientMock{
+		"10.10.0.30": {outputs: map[string]string{"sh -c 'echo '\\''ok'\\'''": "ok\n"}},
+	}, "")
+
+	nodes := c.SelectNodes([]NodeSelector{{"role", "lb"}})
+	rs := c.Exec(context.Background(), nodes, "echo 'ok'", 5)
+
+	assert.Equal(t, 0, rs[0].ExitCode)
+	assert.Equal(t, EXEC_CONNECTION_FAILED, rs[1].ExitCode)
+	assert.NotEmpty(t, rs[1].Error)
+	assert.Equal(t, EXEC_CONNECTION_FAILED, rs.ExitCode())
+
+	assert.Contains(t, c.Ui().ReadStdout(t), name+"-lb-1 | ok\n")
+}
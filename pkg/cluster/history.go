@@ -0,0 +1,153 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+)
+
+type RevisionOutcome string
+
+const (
+	REVISION_SUCCEEDED RevisionOutcome = "succeeded"
+	REVISION_FAILED    RevisionOutcome = "failed"
+)
+
+// Revision describes a configuration that has been applied to the cluster.
+// Revisions are numbered in the order in which they have been applied,
+// starting with 1. Configurations of failed applies are recorded as well.
+type Revision struct {
+	Number          int             `json:"number" yaml:"number"`
+	Created         time.Time       `json:"created" yaml:"created"`
+	Action          ApplyAction     `json:"action" yaml:"action"`
+	KubitectVersion string          `json:"kubitectVersion" yaml:"kubitectVersion"`
+	Outcome         RevisionOutcome `json:"outcome" yaml:"outcome"`
+	Error           string          `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Revisions returns all recorded revisions of the cluster ordered by
+// their number.
+func (c ClusterMeta) Revisions() ([]Revision, error) {
+	entries, err := os.ReadDir(c.HistoryDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read cluster history: %v", err)
+	}
+
+	var revs []Revision
+	for _, e := range entries {
+		n, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+
+		r, err := c.Revision(n)
+		if err != nil {
+			return nil, err
+		}
+
+		revs = append(revs, *r)
+	}
+
+	slices.SortFunc(revs, func(a, b Revision) int {
+		return a.Number - b.Number
+	})
+
+	return revs, nil
+}
+
+// Revision returns the revision with the given number.
+func (c ClusterMeta) Revision(n int) (*Revision, error) {
+	path := c.RevisionPath(n)
+
+	if !file.Exists(path) {
+		return nil, fmt.Errorf("revision %d of cluster %q does not exist", n, c.Name)
+	}
+
+	r, err := file.ReadYaml(path, Revision{})
+	if err != nil {
+		return nil, fmt.Errorf("read revision %d: %v", n, err)
+	}
+
+	return r, nil
+}
+
+// RevisionConfig returns the configuration of the revision with the given
+// number.
+func (c ClusterMeta) RevisionConfig(n int) (*config.Config, error) {
+	if _, err := c.Revision(n); err != nil {
+		return nil, err
+	}
+
+	return readConfig(c.RevisionConfigPath(n), config.Config{})
+}
+
+// CompareRevisions compares configurations of the given revisions.
+func (c ClusterMeta) CompareRevisions(from int, to int) (*cmp.Result, error) {
+	a, err := c.RevisionConfig(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := c.RevisionConfig(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmp.Compare(a, b, configCmpOptions)
+}
+
+// recordRevision records the new configuration as the next revision of the
+// cluster, along with the action it has been applied with and the outcome
+// of the apply. Error of the failed apply is recorded as well.
+func (c *Cluster) recordRevision(action ApplyAction, applyErr error) error {
+	revs, err := c.Revisions()
+	if err != nil {
+		return err
+	}
+
+	r := Revision{
+		Number:          1,
+		Created:         time.Now().UTC().Truncate(time.Second),
+		Action:          action,
+		KubitectVersion: env.ConstProjectVersion,
+		Outcome:         REVISION_SUCCEEDED,
+	}
+
+	if len(revs) > 0 {
+		r.Number = revs[len(revs)-1].Number + 1
+	}
+
+	if applyErr != nil {
+		r.Outcome = REVISION_FAILED
+		r.Error = applyErr.Error()
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.RevisionPath(r.Number)), 0744)
+	if err != nil {
+		return fmt.Errorf("create revision directory: %v", err)
+	}
+
+	err = file.WriteYaml(c.NewConfig, c.RevisionConfigPath(r.Number), 0644)
+	if err != nil {
+		return fmt.Errorf("write revision %d: %v", r.Number, err)
+	}
+
+	err = file.WriteYaml(r, c.RevisionPath(r.Number), 0644)
+	if err != nil {
+		return fmt.Errorf("write revision %d: %v", r.Number, err)
+	}
+
+	return nil
+}
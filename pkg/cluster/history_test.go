@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Empty(t *testing.T) {
+	c := MockCluster(t)
+
+	revs, err := c.Revisions()
+	require.NoError(t, err)
+	assert.Empty(t, revs)
+
+	_, err = c.Revision(1)
+	assert.EqualError(t, err, `revision 1 of cluster "cluster-mock" does not exist`)
+}
+
+func TestApply_RecordsRevisions(t *testing.T) {
+	c := MockCluster(t)
+	mockResize(t, c)
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	require.NoError(t, c.Apply(CREATE.String()))
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM++
+	require.NoError(t, c.Apply(CREATE.String()))
+
+	revs, err := c.Revisions()
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+
+	for i, r := range revs {
+		assert.Equal(t, i+1, r.Number)
+		assert.Equal(t, CREATE, r.Action)
+		assert.Equal(t, REVISION_SUCCEEDED, r.Outcome)
+		assert.Equal(t, env.ConstProjectVersion, r.KubitectVersion)
+		assert.False(t, r.Created.IsZero())
+	}
+
+	res, err := c.CompareRevisions(1, 2)
+	require.NoError(t, err)
+
+	changes := ConfigChanges(res)
+	require.Len(t, changes, 1)
+	assert.Equal(t, cmp.Modify, changes[0].Type)
+	assert.Equal(t, "cluster.nodes.master.instances.1.ram", changes[0].Path)
+}
+
+func TestApply_RecordsFailedRevision(t *testing.T) {
+	c := MockCluster(t)
+	c.prov = failingProvisioner{c.prov}
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	require.EqualError(t, c.Apply(CREATE.String()), "apply failed")
+
+	r, err := c.Revision(1)
+	require.NoError(t, err)
+	assert.Equal(t, REVISION_FAILED, r.Outcome)
+	assert.Equal(t, "apply failed", r.Error)
+
+	cfg, err := c.RevisionConfig(1)
+	require.NoError(t, err)
+	assert.Equal(t, c.NewConfig.Cluster.Name, cfg.Cluster.Name)
+}
+
+func TestRollback_DefaultDataDisks(t *testing.T) {
+	c := MockCluster(t)
+	mockDefaultDataDisks(t, c)
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	require.NoError(t, c.Apply(CREATE.String()))
+
+	// Revision configuration is read the same way as when rolling back.
+	rc, err := NewCluster(c.AppContext(), c.RevisionConfigPath(1))
+	require.NoError(t, err)
+
+	disks := []config.DataDisk{{Name: "data", Size: 10}}
+	for _, n := range rc.NewConfig.Cluster.Nodes.Master.Instances {
+		assert.Equal(t, disks, n.DataDisks)
+	}
+
+	rc.exec = c.exec
+	rc.prov = c.prov
+
+	require.NoError(t, rc.Apply(CREATE.String()))
+	assert.Contains(t, c.Ui().ReadStdout(t), "No changes detected")
+}
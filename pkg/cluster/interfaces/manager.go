@@ -1,6 +1,11 @@
 package interfaces
 
-import "github.com/MusicDin/kubitect/pkg/cluster/event"
+import (
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+)
 
 type Manager interface {
 	Init() error
@@ -9,4 +14,45 @@ type Manager interface {
 	Upgrade() error
 	ScaleUp(event.Events) error
 	ScaleDown(event.Events) error
+
+	// CordonNode marks the node as unschedulable.
+	CordonNode(config.Instance) error
+
+	// DrainNode marks the node as unschedulable and evicts its pods.
+	DrainNode(config.Instance, DrainOptions) error
+
+	// UncordonNode waits for the node to become ready and marks it
+	// as schedulable.
+	UncordonNode(config.Instance) error
+
+	// RenewCertificates renews certificates of the control plane and
+	// refreshes client credentials in the cluster's kubeconfig.
+	RenewCertificates() error
+}
+
+// DrainOptions configure how pods are evicted from a drained node.
+type DrainOptions struct {
+	// Timeout is the maximum time to wait for the node to be drained.
+	// Zero means no timeout.
+	Timeout time.Duration
+
+	// GracePeriod overrides the termination grace period of the evicted
+	// pods. Zero means that the grace period of each pod is respected.
+	GracePeriod time.Duration
+
+	// DisableEviction deletes pods instead of evicting them, which
+	// bypasses PodDisruptionBudgets.
+	DisableEviction bool
+
+	// DeleteEmptyDirData allows evicting pods that use emptyDir volumes,
+	// whose data is deleted.
+	DeleteEmptyDirData bool
+
+	// Force allows evicting pods that are not managed by a controller.
+	Force bool
 }
+
+// PhaseFunc runs the given function as a named phase of the apply. It
+// allows the caller to record the progress of the apply and to skip the
+// phases that have been completed by a previous (failed) apply.
+type PhaseFunc func(name string, fn func() error) error
@@ -4,16 +4,21 @@ import (
 	"testing"
 
 	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
 )
 
 type managerMock struct{}
 
-func (m managerMock) Init() error                  { return nil }
-func (m managerMock) Sync() error                  { return nil }
-func (m managerMock) Create() error                { return nil }
-func (m managerMock) Upgrade() error               { return nil }
-func (m managerMock) ScaleDown(event.Events) error { return nil }
-func (m managerMock) ScaleUp(event.Events) error   { return nil }
+func (m managerMock) Init() error                                   { return nil }
+func (m managerMock) Sync() error                                   { return nil }
+func (m managerMock) Create() error                                 { return nil }
+func (m managerMock) Upgrade() error                                { return nil }
+func (m managerMock) ScaleDown(event.Events) error                  { return nil }
+func (m managerMock) ScaleUp(event.Events) error                    { return nil }
+func (m managerMock) CordonNode(config.Instance) error              { return nil }
+func (m managerMock) DrainNode(config.Instance, DrainOptions) error { return nil }
+func (m managerMock) UncordonNode(config.Instance) error            { return nil }
+func (m managerMock) RenewCertificates() error                      { return nil }
 
 func MockManager(t *testing.T) Manager {
 	return managerMock{}
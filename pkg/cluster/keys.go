@@ -0,0 +1,303 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+	"github.com/MusicDin/kubitect/pkg/utils/keygen"
+)
+
+// sshKeyTimeout limits the time of each SSH command executed while the
+// SSH keys are rotated.
+const sshKeyTimeout = 30 * time.Second
+
+// previousPublicKeyFilename is the name of the file in the pending SSH
+// key directory, which holds the public key that is being replaced.
+const previousPublicKeyFilename = "previous.pub"
+
+// newNodeClientWithKey returns a client that runs commands on the node with
+// the given IP address over SSH, authenticating with the given private key.
+var newNodeClientWithKey = func(c *Cluster, ip string, keyPath string) nodeClient {
+	ssh := exec.NewSSHClient(string(c.NewConfig.Cluster.NodeTemplate.User), ip).
+		WithPrivateKeyFile(keyPath)
+
+	return &ssh
+}
+
+// updateKnownHost replaces the entry of the node with the given IP address
+// in the local known_hosts file with the node's current host key.
+var updateKnownHost = func(ip string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf(`set -e
+mkdir -p "$HOME/.ssh"
+touch "$HOME/.ssh/known_hosts"
+ssh-keygen -R %[1]s
+ssh-keyscan -t rsa %[1]s >> "$HOME/.ssh/known_hosts"
+rm -f "$HOME/.ssh/known_hosts.old"`, exec.ShellQuote(ip))
+
+	lc := exec.NewLocalClient()
+	lc.SetEnv("HOME", home)
+
+	_, err = lc.Output("sh", "-c", script)
+	return err
+}
+
+// newSshKeyPair generates a new SSH key pair of the type and size set in
+// the given SSH settings.
+func newSshKeyPair(ssh config.NodeTemplateSSH) (keygen.KeyPair, error) {
+	return keygen.NewKeyPairOfType(keygen.KeyType(ssh.KeyType), ssh.KeyBits)
+}
+
+// RotateSshKeys replaces the cluster's SSH key pair with a newly generated
+// one. The new public key is authorized on each node and verified to
+// grant access, before the previous public key is removed from the nodes.
+//
+// The new key pair is of the given type and size. If the key type is not
+// given, the type configured in the node template is used. Changing the
+// key type in the node template has no effect on an existing cluster,
+// since its configuration cannot be changed.
+//
+// The new key pair is staged in the pending SSH key directory until the
+// rotation completes, which makes it safe to rerun the rotation after a
+// partial failure.
+func (c *Cluster) RotateSshKeys(keyType config.SshKeyType, keyBits int) error {
+	ssh := c.NewConfig.Cluster.NodeTemplate.SSH
+	if keyType != "" {
+		// Configured size may not be valid for the given key type.
+		ssh.KeyType = keyType
+		ssh.KeyBits = keyBits
+	} else if keyBits != 0 {
+		ssh.KeyBits = keyBits
+	}
+
+	if err := ssh.Validate(); err != nil {
+		return fmt.Errorf("invalid SSH key type or size: %v", err)
+	}
+
+	err := ui.Ask(fmt.Sprintf("SSH key pair of cluster %q will be replaced on all nodes. Proceed?", c.Name))
+	if err != nil {
+		return err
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	pending, previous, err := c.pendingSshKeys(ssh)
+	if err != nil {
+		return err
+	}
+
+	newKey := filepath.Join(c.PendingSshKeyDir(), filepath.Base(c.PrivateSshKeyPath()))
+	nodes := c.InfraConfig.Nodes.Instances()
+
+	for _, n := range nodes {
+		ui.Printf(ui.INFO, "Authorizing new SSH key on node %q...\n", c.nodeName(n))
+
+		if err := c.authorizeSshKey(n, newKey); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range nodes {
+		ui.Printf(ui.INFO, "Removing previous SSH key from node %q...\n", c.nodeName(n))
+
+		if err := c.removeSshKey(n, newKey, previous); err != nil {
+			return err
+		}
+	}
+
+	kpDir := filepath.Dir(c.PrivateSshKeyPath())
+	kpName := filepath.Base(c.PrivateSshKeyPath())
+
+	if err := pending.Write(kpDir, kpName); err != nil {
+		return fmt.Errorf("write SSH keys: %v", err)
+	}
+
+	if err := os.RemoveAll(c.PendingSshKeyDir()); err != nil {
+		return fmt.Errorf("remove pending SSH keys: %v", err)
+	}
+
+	if !c.NewConfig.Cluster.NodeTemplate.SSH.AddToKnownHosts {
+		return nil
+	}
+
+	for _, n := range nodes {
+		if err := updateKnownHost(string(n.GetIP())); err != nil {
+			ui.Printf(ui.WARN, "Failed to update known hosts entry of node %q: %v\n", c.nodeName(n), err)
+		}
+	}
+
+	return nil
+}
+
+// pendingSshKeys returns the pending key pair along with the public key it
+// replaces. If the pending key pair does not exist yet, a new one is
+// generated according to the given SSH settings and the current public
+// key is stored next to it.
+func (c *Cluster) pendingSshKeys(ssh config.NodeTemplateSSH) (keygen.KeyPair, string, error) {
+	dir := c.PendingSshKeyDir()
+	name := filepath.Base(c.PrivateSshKeyPath())
+	previousPath := filepath.Join(dir, previousPublicKeyFilename)
+
+	if keygen.KeyPairExists(dir, name) && file.Exists(previousPath) {
+		ui.Println(ui.INFO, "Resuming interrupted SSH key rotation...")
+
+		kp, err := keygen.ReadKeyPair(dir, name)
+		if err != nil {
+			return nil, "", err
+		}
+
+		previous, err := os.ReadFile(previousPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("read previous public key: %v", err)
+		}
+
+		return kp, string(previous), nil
+	}
+
+	previous, err := os.ReadFile(c.PrivateSshKeyPath() + ".pub")
+	if err != nil {
+		return nil, "", fmt.Errorf("read current public key: %v", err)
+	}
+
+	kp, err := newSshKeyPair(ssh)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, "", fmt.Errorf("create pending SSH key directory: %v", err)
+	}
+
+	// The previous public key is written first, so that the pending key
+	// pair is never present without it.
+	if err := os.WriteFile(previousPath, previous, 0600); err != nil {
+		return nil, "", fmt.Errorf("write previous public key: %v", err)
+	}
+
+	if err := kp.Write(dir, name); err != nil {
+		return nil, "", fmt.Errorf("write pending SSH keys: %v", err)
+	}
+
+	return kp, string(previous), nil
+}
+
+// authorizeSshKey adds the public key of the pending key pair to the
+// authorized keys on the node, accessed with the current private key, and
+// verifies that the pending private key, located at the given path, grants
+// access to the node. If the pending key is already authorized, the node
+// is left unchanged.
+func (c *Cluster) authorizeSshKey(n config.Instance, keyPath string) error {
+	ip := string(n.GetIP())
+
+	if err := verifySshKey(c, ip, keyPath); err == nil {
+		return nil
+	}
+
+	b, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		return fmt.Errorf("read new public key: %v", err)
+	}
+
+	pub := string(b)
+
+	blob, err := publicKeyBlob(pub)
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf(`set -e
+mkdir -p ~/.ssh
+chmod 700 ~/.ssh
+touch ~/.ssh/authorized_keys
+chmod 600 ~/.ssh/authorized_keys
+grep -qF %[1]s ~/.ssh/authorized_keys || echo %[2]s >> ~/.ssh/authorized_keys`,
+		exec.ShellQuote(blob), exec.ShellQuote(strings.TrimSpace(pub)))
+
+	client := newNodeClientWithKey(c, ip, c.PrivateSshKeyPath())
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sshKeyTimeout)
+	defer cancel()
+
+	if _, err := client.OutputCtx(ctx, "sh", "-c", exec.ShellQuote(script)); err != nil {
+		return fmt.Errorf("authorize new SSH key on node %q: %v", c.nodeName(n), err)
+	}
+
+	if err := verifySshKey(c, ip, keyPath); err != nil {
+		return fmt.Errorf("verify new SSH key on node %q: %v", c.nodeName(n), err)
+	}
+
+	return nil
+}
+
+// removeSshKey removes the given public key from the authorized keys on
+// the node. The node is accessed with the pending private key located at
+// the given path.
+func (c *Cluster) removeSshKey(n config.Instance, keyPath string, pub string) error {
+	blob, err := publicKeyBlob(pub)
+	if err != nil {
+		return err
+	}
+
+	// Content is copied back into the original file to preserve its
+	// ownership and permissions.
+	script := fmt.Sprintf(`set -e
+touch ~/.ssh/authorized_keys
+grep -vF %s ~/.ssh/authorized_keys > ~/.ssh/authorized_keys.tmp || true
+cat ~/.ssh/authorized_keys.tmp > ~/.ssh/authorized_keys
+rm -f ~/.ssh/authorized_keys.tmp`, exec.ShellQuote(blob))
+
+	client := newNodeClientWithKey(c, string(n.GetIP()), keyPath)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sshKeyTimeout)
+	defer cancel()
+
+	if _, err := client.OutputCtx(ctx, "sh", "-c", exec.ShellQuote(script)); err != nil {
+		return fmt.Errorf("remove previous SSH key from node %q: %v", c.nodeName(n), err)
+	}
+
+	return nil
+}
+
+// verifySshKey verifies that the private key at the given path grants
+// access to the node with the given IP address.
+func verifySshKey(c *Cluster, ip string, keyPath string) error {
+	client := newNodeClientWithKey(c, ip, keyPath)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sshKeyTimeout)
+	defer cancel()
+
+	_, err := client.OutputCtx(ctx, "true")
+	return err
+}
+
+// publicKeyBlob returns the base64 encoded part of the public key in the
+// authorized keys format ("<type> <blob> [comment]"), which identifies the
+// key regardless of its comment.
+func publicKeyBlob(pub string) (string, error) {
+	fields := strings.Fields(pub)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("invalid public key %q", pub)
+	}
+
+	return fields[1], nil
+}
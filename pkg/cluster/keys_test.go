@@ -0,0 +1,235 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/keygen"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keysClientMock simulates authorized keys on the node. Access to the node
+// is granted only if the public key of the client's private key is
+// authorized.
+type keysClientMock struct {
+	nodeClientMock
+
+	ip         string
+	keyPath    string
+	authorized map[string][]string
+	fail       map[string]bool
+	pendingKey string
+}
+
+func (m *keysClientMock) OutputCtx(ctx context.Context, command string, args ...string) ([]byte, error) {
+	if m.fail[m.ip] {
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	blob, err := publicKeyBlobFile(m.keyPath + ".pub")
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(m.authorized[m.ip], blob) {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	cmd := strings.Join(append([]string{command}, args...), " ")
+
+	switch {
+	case strings.Contains(cmd, "grep -qF"):
+		pending, err := publicKeyBlobFile(m.pendingKey + ".pub")
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(m.authorized[m.ip], pending) {
+			m.authorized[m.ip] = append(m.authorized[m.ip], pending)
+		}
+	case strings.Contains(cmd, "grep -vF"):
+		var keys []string
+		for _, k := range m.authorized[m.ip] {
+			if !strings.Contains(cmd, k) {
+				keys = append(keys, k)
+			}
+		}
+
+		m.authorized[m.ip] = keys
+	}
+
+	return nil, nil
+}
+
+func publicKeyBlobFile(path string) (string, error) {
+	pub, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return publicKeyBlob(string(pub))
+}
+
+// mockKeys generates the cluster's SSH key pair, authorizes it on all nodes
+// and replaces node clients with keysClientMock for the duration of the
+// test. Authorized keys of nodes are returned along with the map of nodes
+// that refuse connections.
+func mockKeys(t *testing.T, c *ClusterMock) (map[string][]string, map[string]bool) {
+	t.Helper()
+
+	kp, err := keygen.NewKeyPair(2048)
+	require.NoError(t, err)
+	require.NoError(t, kp.Write(filepath.Dir(c.PrivateSshKeyPath()), filepath.Base(c.PrivateSshKeyPath())))
+
+	blob, err := publicKeyBlobFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+
+	authorized := make(map[string][]string)
+	for _, n := range c.InfraConfig.Nodes.Instances() {
+		authorized[string(n.GetIP())] = []string{blob}
+	}
+
+	fail := make(map[string]bool)
+	pendingKey := filepath.Join(c.PendingSshKeyDir(), filepath.Base(c.PrivateSshKeyPath()))
+
+	tmpClient := newNodeClientWithKey
+	tmpKnownHost := updateKnownHost
+
+	newNodeClientWithKey = func(c *Cluster, ip string, keyPath string) nodeClient {
+		return &keysClientMock{
+			ip:         ip,
+			keyPath:    keyPath,
+			authorized: authorized,
+			fail:       fail,
+			pendingKey: pendingKey,
+		}
+	}
+
+	updateKnownHost = func(ip string) error {
+		return nil
+	}
+
+	t.Cleanup(func() {
+		newNodeClientWithKey = tmpClient
+		updateKnownHost = tmpKnownHost
+	})
+
+	return authorized, fail
+}
+
+func TestRotateSshKeys(t *testing.T) {
+	c := mockStatusCluster(t)
+	c.NewConfig.Cluster.NodeTemplate.SSH.KeyType = config.ED25519
+	authorized, _ := mockKeys(t, c)
+
+	previous, err := publicKeyBlobFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+
+	require.NoError(t, c.RotateSshKeys("", 0))
+
+	current, err := publicKeyBlobFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+	assert.NotEqual(t, previous, current)
+
+	pub, err := os.ReadFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pub), "ssh-ed25519 "))
+
+	for ip, keys := range authorized {
+		assert.Equal(t, []string{current}, keys, ip)
+	}
+
+	assert.NoDirExists(t, c.PendingSshKeyDir())
+}
+
+func TestRotateSshKeys_Resume(t *testing.T) {
+	c := mockStatusCluster(t)
+	authorized, fail := mockKeys(t, c)
+
+	previous, err := publicKeyBlobFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+
+	fail["10.10.0.30"] = true
+
+	err = c.RotateSshKeys("", 0)
+	require.ErrorContains(t, err, `authorize new SSH key on node "cluster-mock-lb-1"`)
+
+	// Previous key is still valid and the pending key is kept.
+	pending, err := publicKeyBlobFile(filepath.Join(c.PendingSshKeyDir(), "id_rsa.pub"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{previous, pending}, authorized["10.10.0.10"])
+	assert.Equal(t, []string{previous}, authorized["10.10.0.30"])
+
+	current, err := publicKeyBlobFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+	assert.Equal(t, previous, current)
+
+	// Previous key has already been removed from one of the nodes.
+	authorized["10.10.0.10"] = []string{pending}
+	fail["10.10.0.30"] = false
+
+	require.NoError(t, c.RotateSshKeys("", 0))
+
+	current, err = publicKeyBlobFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+	assert.Equal(t, pending, current)
+
+	for ip, keys := range authorized {
+		assert.Equal(t, []string{pending}, keys, ip)
+	}
+
+	assert.NoDirExists(t, c.PendingSshKeyDir())
+}
+
+func TestRotateSshKeys_KeyType(t *testing.T) {
+	c := mockStatusCluster(t)
+	c.NewConfig.Cluster.NodeTemplate.SSH.KeyType = config.RSA
+	c.NewConfig.Cluster.NodeTemplate.SSH.KeyBits = 4096
+	mockKeys(t, c)
+
+	// Size configured for RSA keys is not used for the given key type.
+	require.NoError(t, c.RotateSshKeys(config.ECDSA, 0))
+
+	pub, err := os.ReadFile(c.PrivateSshKeyPath() + ".pub")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pub), "ecdsa-sha2-nistp256 "))
+}
+
+func TestRotateSshKeys_InvalidKeyType(t *testing.T) {
+	c := mockStatusCluster(t)
+	mockKeys(t, c)
+
+	err := c.RotateSshKeys("dsa", 0)
+	assert.ErrorContains(t, err, "invalid SSH key type or size")
+
+	err = c.RotateSshKeys(config.ED25519, 256)
+	assert.ErrorContains(t, err, "invalid SSH key type or size")
+
+	assert.NoDirExists(t, c.PendingSshKeyDir())
+}
+
+func TestRotateSshKeys_MissingKeys(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	err := c.RotateSshKeys("", 0)
+	assert.ErrorContains(t, err, "read current public key")
+	assert.NoDirExists(t, c.PendingSshKeyDir())
+}
+
+func TestPublicKeyBlob(t *testing.T) {
+	blob, err := publicKeyBlob("ssh-rsa AAAAB3Nza user@host\n")
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAB3Nza", blob)
+
+	_, err = publicKeyBlob("invalid")
+	assert.EqualError(t, err, `invalid public key "invalid"`)
+}
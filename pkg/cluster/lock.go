@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lock identifies the process that holds the cluster lock. While the lock
+// is held, no other process can modify the cluster.
+type Lock struct {
+	PID     int       `yaml:"pid"`
+	User    string    `yaml:"user"`
+	Host    string    `yaml:"host"`
+	Command string    `yaml:"command"`
+	Created time.Time `yaml:"created"`
+}
+
+func (l Lock) String() string {
+	return fmt.Sprintf("%s@%s (PID %d, since %s)", l.User, l.Host, l.PID, l.Created.Format(time.RFC3339))
+}
+
+// newLock returns a lock that identifies the current process.
+func newLock() Lock {
+	l := Lock{
+		PID:     os.Getpid(),
+		User:    os.Getenv("USER"),
+		Command: strings.Join(os.Args, " "),
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if u, err := user.Current(); err == nil {
+		l.User = u.Username
+	}
+
+	if h, err := os.Hostname(); err == nil {
+		l.Host = h
+	}
+
+	return l
+}
+
+// readLock reads the lock on the given path. If lock does not exist, nil
+// is returned both for an error and the lock.
+func readLock(path string) (*Lock, error) {
+	if !file.Exists(path) {
+		return nil, nil
+	}
+
+	l, err := file.ReadYaml(path, Lock{})
+	if err != nil {
+		return nil, fmt.Errorf("read cluster lock: %v", err)
+	}
+
+	return l, nil
+}
+
+// Lock acquires the cluster lock and returns a function that releases it.
+// If the lock is already held by another process, an error naming the
+// lock holder is returned. Cluster directory is created if it does not
+// exist, and removed on release if it is still empty.
+func (c *ClusterMeta) Lock() (func(), error) {
+	createdDir := !file.Exists(c.Path)
+
+	if err := os.MkdirAll(c.Path, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create cluster directory: %v", err)
+	}
+
+	content, err := yaml.Marshal(newLock())
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock file is created exclusively, so that only one process can
+	// acquire the lock.
+	f, err := os.OpenFile(c.LockPath(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		l, err := readLock(c.LockPath())
+		if err != nil {
+			return nil, err
+		}
+
+		if l == nil {
+			return nil, fmt.Errorf("cluster %q is locked by another operation", c.Name)
+		}
+
+		return nil, NewClusterLockedError(c.Name, *l)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("acquire cluster lock: %v", err)
+	}
+
+	_, err = f.Write(content)
+	f.Close()
+
+	if err != nil {
+		os.Remove(c.LockPath())
+		return nil, fmt.Errorf("acquire cluster lock: %v", err)
+	}
+
+	unlock := func() {
+		err := os.Remove(c.LockPath())
+		if err != nil && !os.IsNotExist(err) {
+			ui.Printf(ui.WARN, "Failed to release the lock of cluster %q: %v\n", c.Name, err)
+		}
+
+		if createdDir {
+			// Fails if directory is not empty.
+			_ = os.Remove(c.Path)
+		}
+	}
+
+	return unlock, nil
+}
+
+// ForceUnlock removes the cluster lock regardless of the process that
+// holds it.
+func (c *ClusterMeta) ForceUnlock() error {
+	l, err := readLock(c.LockPath())
+
+	switch {
+	case err != nil:
+		// Remove the lock even if it cannot be read.
+		ui.Printf(ui.WARN, "Lock of cluster %q is invalid: %v\n", c.Name, err)
+	case l == nil:
+		return nil
+	default:
+		ui.Printf(ui.WARN, "Removing lock of cluster %q held by %s...\n", c.Name, l)
+	}
+
+	err = os.Remove(c.LockPath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove cluster lock: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	c := MockCluster(t)
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+
+	l, err := readLock(c.LockPath())
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), l.PID)
+	assert.NotEmpty(t, l.Command)
+
+	// Lock cannot be acquired twice.
+	_, err = c.Lock()
+	assert.ErrorContains(t, err, "Cluster Locked")
+	assert.ErrorContains(t, err, "--force-unlock")
+
+	// Empty cluster directory is removed on unlock.
+	unlock()
+	assert.False(t, file.Exists(c.LockPath()))
+	assert.False(t, file.Exists(c.Path))
+}
+
+func TestLock_ExistingDirectory(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, os.MkdirAll(c.Path, os.ModePerm))
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+
+	unlock()
+	assert.True(t, file.Exists(c.Path))
+}
+
+func TestForceUnlock(t *testing.T) {
+	c := MockCluster(t)
+
+	_, err := c.Lock()
+	require.NoError(t, err)
+
+	require.NoError(t, c.ForceUnlock())
+	assert.Contains(t, c.Ui().ReadStderr(t), "Removing lock of cluster")
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+	unlock()
+
+	// Unlocking an unlocked cluster is a no-op.
+	assert.NoError(t, c.ForceUnlock())
+}
+
+func TestApply_Locked(t *testing.T) {
+	c := MockCluster(t)
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	assert.ErrorContains(t, c.Apply(CREATE.String()), "Cluster Locked")
+	assert.False(t, c.ContainsAppliedConfig())
+}
+
+func TestPlan_Locked(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	before, err := os.ReadFile(c.AppliedConfigPath())
+	require.NoError(t, err)
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = append(
+		c.NewConfig.Cluster.Nodes.Worker.Instances,
+		config.WorkerInstance{Id: "worker"},
+	)
+
+	_, err = c.Plan(SCALE.String())
+	assert.ErrorContains(t, err, "Cluster Locked")
+	assert.NoFileExists(t, filepath.Join(c.ConfigDir(), DefaultNewConfigFilename))
+
+	after, err := os.ReadFile(c.AppliedConfigPath())
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestDestroy_Locked(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, os.MkdirAll(c.Path, os.ModePerm))
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	assert.ErrorContains(t, c.Destroy(), "Cluster Locked")
+	assert.True(t, file.Exists(c.Path))
+}
@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
+
+var (
+	// Maximum time to wait for the node to boot after it has been
+	// rebooted, and the interval in which its boot ID is checked.
+	nodeRebootTimeout  = 10 * time.Minute
+	nodeRebootInterval = 5 * time.Second
+)
+
+// CordonNode marks the node with the given name as unschedulable.
+func (c *Cluster) CordonNode(name string) error {
+	return c.maintainNode(name, false, func(n config.Instance) error {
+		return c.Manager().CordonNode(n)
+	})
+}
+
+// DrainNode marks the node with the given name as unschedulable and
+// evicts its pods according to the drain options.
+func (c *Cluster) DrainNode(name string, o interfaces.DrainOptions) error {
+	return c.maintainNode(name, false, func(n config.Instance) error {
+		return c.Manager().DrainNode(n, o)
+	})
+}
+
+// UncordonNode waits for the node with the given name to become ready
+// and marks it as schedulable.
+func (c *Cluster) UncordonNode(name string) error {
+	return c.maintainNode(name, false, func(n config.Instance) error {
+		return c.Manager().UncordonNode(n)
+	})
+}
+
+// RebootNode drains the node with the given name, reboots it and waits
+// for it to boot. Once the node is ready, it is marked as schedulable
+// again. Load balancers are not part of the Kubernetes cluster, therefore
+// they are only rebooted.
+func (c *Cluster) RebootNode(name string, o interfaces.DrainOptions) error {
+	return c.maintainNode(name, true, func(n config.Instance) error {
+		if err := c.Manager().DrainNode(n, o); err != nil {
+			return err
+		}
+
+		if err := c.rebootNode(n); err != nil {
+			return err
+		}
+
+		return c.Manager().UncordonNode(n)
+	})
+}
+
+// maintainNode runs the given maintenance operation on the node with the
+// given name while holding the cluster lock. Unless allowed, operations
+// on load balancers are rejected, since they are not part of the
+// Kubernetes cluster.
+func (c *Cluster) maintainNode(name string, allowLB bool, fn func(n config.Instance) error) error {
+	n, err := c.FindNode(name)
+	if err != nil {
+		return err
+	}
+
+	if n.GetTypeName() == "lb" && !allowLB {
+		return fmt.Errorf("node %q is a load balancer, which is not part of the Kubernetes cluster", c.nodeName(n))
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	return fn(n)
+}
+
+// rebootNode reboots the given node and waits until it boots again. The
+// node is considered booted once it reports a different boot ID.
+func (c *Cluster) rebootNode(n config.Instance) error {
+	name := c.nodeName(n)
+	ip := string(n.GetIP())
+
+	bootID, err := nodeBootID(c, ip)
+	if err != nil {
+		return fmt.Errorf("read boot ID of node %q: %v", name, err)
+	}
+
+	ui.Printf(ui.INFO, "Rebooting node %q...\n", name)
+
+	client := newNodeClient(c, ip)
+	defer client.Close()
+
+	// Reboot is delayed, so that the command exits before the connection
+	// is terminated.
+	_, err = client.OutputCtx(context.Background(), "sudo", "systemd-run", "--on-active=2", "systemctl", "reboot")
+	if err != nil {
+		return fmt.Errorf("reboot node %q: %v", name, err)
+	}
+
+	return c.waitNodeBoot(n, bootID)
+}
+
+// waitNodeBoot waits until the given node boots again, which is once it
+// reports a boot ID different from the given one.
+func (c *Cluster) waitNodeBoot(n config.Instance, bootID string) error {
+	name := c.nodeName(n)
+	ip := string(n.GetIP())
+
+	ui.Printf(ui.INFO, "Waiting for node %q to boot...\n", name)
+
+	deadline := time.Now().Add(nodeRebootTimeout)
+
+	for {
+		time.Sleep(nodeRebootInterval)
+
+		// Errors are expected while the node is restarting.
+		id, err := nodeBootID(c, ip)
+		if err == nil && id != bootID {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %q has not booted within %s", name, nodeRebootTimeout)
+		}
+	}
+}
+
+// nodeBootID returns the boot ID of the node with the given IP address,
+// which changes each time the node boots.
+func nodeBootID(c *Cluster, ip string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nodeRebootInterval)
+	defer cancel()
+
+	client := newNodeClient(c, ip)
+	defer client.Close()
+
+	out, err := client.OutputCtx(ctx, "cat", "/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rebootClientMock records executed commands. The reported boot ID
+// changes each time the node is rebooted.
+type rebootClientMock struct {
+	nodeClientMock
+
+	ip       string
+	commands *[]string
+	boots    *int
+}
+
+func (m *rebootClientMock) OutputCtx(ctx context.Context, command string, args ...string) ([]byte, error) {
+	*m.commands = append(*m.commands, fmt.Sprintf("%s: %s %s", m.ip, command, strings.Join(args, " ")))
+
+	if command == "sudo" {
+		*m.boots++
+		return nil, nil
+	}
+
+	return []byte(fmt.Sprintf("boot-%d\n", *m.boots)), nil
+}
+
+// mockReboot replaces node clients with rebootClientMock for the duration
+// of the test and returns the list of executed commands along with the
+// number of node boots.
+func mockReboot(t *testing.T) (*[]string, *int) {
+	t.Helper()
+
+	tmpClient := newNodeClient
+	tmpInterval := nodeRebootInterval
+
+	var commands []string
+	var boots int
+
+	newNodeClient = func(c *Cluster, ip string) nodeClient {
+		return &rebootClientMock{ip: ip, commands: &commands, boots: &boots}
+	}
+
+	nodeRebootInterval = time.Millisecond
+
+	t.Cleanup(func() {
+		newNodeClient = tmpClient
+		nodeRebootInterval = tmpInterval
+	})
+
+	return &commands, &boots
+}
+
+func TestDrainNode(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	r := &resizeRecorder{}
+	c.exec = resizeManager{c.exec, r}
+
+	require.NoError(t, c.CordonNode("worker-1"))
+	require.NoError(t, c.DrainNode(c.Name+"-master-1", interfaces.DrainOptions{}))
+	require.NoError(t, c.UncordonNode("worker-1"))
+
+	assert.Equal(t, []string{"cordon worker-1", "drain master-1", "uncordon worker-1"}, r.calls)
+}
+
+func TestDrainNode_LoadBalancer(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	err := c.DrainNode("lb-1", interfaces.DrainOptions{})
+	assert.EqualError(t, err, fmt.Sprintf("node %q is a load balancer, which is not part of the Kubernetes cluster", c.Name+"-lb-1"))
+}
+
+func TestDrainNode_Locked(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	unlock, err := c.Lock()
+	require.NoError(t, err)
+	defer unlock()
+
+	err = c.DrainNode("worker-1", interfaces.DrainOptions{})
+	assert.ErrorContains(t, err, "locked")
+}
+
+func TestRebootNode(t *testing.T) {
+	c := mockStatusCluster(t)
+	commands, _ := mockReboot(t)
+
+	r := &resizeRecorder{}
+	c.exec = resizeManager{c.exec, r}
+
+	require.NoError(t, c.RebootNode("worker-1", interfaces.DrainOptions{}))
+
+	assert.Equal(t, []string{"drain worker-1", "uncordon worker-1"}, r.calls)
+	assert.Equal(t, []string{
+		"10.10.0.20: cat /proc/sys/kernel/random/boot_id",
+		"10.10.0.20: sudo systemd-run --on-active=2 systemctl reboot",
+		"10.10.0.20: cat /proc/sys/kernel/random/boot_id",
+	}, *commands)
+}
@@ -0,0 +1,139 @@
+package managers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/certs"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// kubesprayRenewCertsScript renews certificates of the control plane on
+// a master node using kubeadm. Control plane pods are removed afterwards,
+// so that the kubelet recreates them with the renewed certificates.
+const kubesprayRenewCertsScript = `set -e
+kubeadm certs renew all
+crictl pods --namespace kube-system --name 'kube-apiserver-*|kube-controller-manager-*|kube-scheduler-*' --quiet | xargs -r crictl rmp --force
+timeout 300 sh -c 'until curl --silent --insecure --max-time 5 https://127.0.0.1:6443/livez > /dev/null; do sleep 5; done'`
+
+// k3sRenewCertsScript rotates certificates of the k3s server, which
+// requires the server to be stopped.
+const k3sRenewCertsScript = `set -e
+systemctl stop k3s
+k3s certificate rotate
+systemctl start k3s`
+
+// RenewCertificates renews certificates of the control plane using
+// kubeadm and refreshes the cluster's kubeconfig.
+func (e *kubespray) RenewCertificates() error {
+	err := e.renewCertificates(kubesprayRenewCertsScript, "/etc/kubernetes/admin.conf")
+	if err != nil {
+		return err
+	}
+
+	return e.mergeRenewedKubeconfig()
+}
+
+// RenewCertificates rotates certificates of k3s servers and refreshes the
+// cluster's kubeconfig.
+func (e *k3s) RenewCertificates() error {
+	err := e.renewCertificates(k3sRenewCertsScript, "/etc/rancher/k3s/k3s.yaml")
+	if err != nil {
+		return err
+	}
+
+	return e.mergeRenewedKubeconfig()
+}
+
+// renewCertificates runs the given script on master nodes one by one, so
+// that the control plane remains available. Afterwards, the client
+// credentials in the cluster's kubeconfig are replaced with the renewed
+// ones read from the kubeconfig at the given path on the first master
+// node.
+func (e common) renewCertificates(script string, kubeconfigPath string) error {
+	masters := e.InfraConfig.Nodes.Master.Instances
+	if len(masters) == 0 {
+		return fmt.Errorf("cluster %q has no control plane nodes", e.ClusterName)
+	}
+
+	for _, m := range masters {
+		name := e.nodeName(m)
+
+		ui.Printf(ui.INFO, "Renewing certificates on node %q...\n", name)
+
+		ssh := exec.NewSSHClient(e.SshUser(), string(m.IP)).
+			WithPrivateKeyFile(e.SshPKey()).
+			WithSuperUser(true)
+
+		ssh.SetCombinedStdout(ui.Streams().Out().File())
+
+		err := ssh.Run("sh", "-c", exec.ShellQuote(script))
+		ssh.Close()
+
+		if err != nil {
+			return fmt.Errorf("renew certificates on node %q: %v", name, err)
+		}
+	}
+
+	ui.Printf(ui.INFO, "Refreshing kubeconfig...\n")
+
+	ssh := exec.NewSSHClient(e.SshUser(), string(masters[0].IP)).
+		WithPrivateKeyFile(e.SshPKey()).
+		WithSuperUser(true)
+
+	defer ssh.Close()
+
+	kubeconfig, err := ssh.Output("cat", kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("read kubeconfig from node %q: %v", e.nodeName(masters[0]), err)
+	}
+
+	return e.refreshKubeconfig(kubeconfig)
+}
+
+// refreshKubeconfig replaces client credentials in the cluster's
+// kubeconfig with the credentials from the given kubeconfig. The rest of
+// the kubeconfig, such as the API server address, remains unchanged.
+func (e *common) refreshKubeconfig(renewed []byte) error {
+	local, err := os.ReadFile(filepath.Join(e.ConfigDir, "admin.conf"))
+	if err != nil {
+		return err
+	}
+
+	oldCreds, err := certs.KubeconfigCredentials(local)
+	if err != nil {
+		return fmt.Errorf("read cluster kubeconfig: %v", err)
+	}
+
+	newCreds, err := certs.KubeconfigCredentials(renewed)
+	if err != nil {
+		return fmt.Errorf("read renewed kubeconfig: %v", err)
+	}
+
+	replaces := map[string]string{
+		oldCreds.CertificateData: newCreds.CertificateData,
+	}
+
+	if oldCreds.KeyData != newCreds.KeyData {
+		replaces[oldCreds.KeyData] = newCreds.KeyData
+	}
+
+	return e.rewriteKubeconfig(replaces)
+}
+
+// mergeRenewedKubeconfig merges the refreshed kubeconfig with the default
+// config in user directory, if merging is enabled.
+func (e common) mergeRenewedKubeconfig() error {
+	if !e.Config.Kubernetes.Other.MergeKubeconfig {
+		return nil
+	}
+
+	if err := e.mergeKubeconfig(); err != nil {
+		// Just warn about failure, since certificates have been renewed.
+		ui.Print(ui.WARN, "Failed to merge kubeconfig:", err)
+	}
+
+	return nil
+}
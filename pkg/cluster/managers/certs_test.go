@@ -0,0 +1,62 @@
+package managers
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshKubeconfig(t *testing.T) {
+	m := MockManager(t)
+	require.NoError(t, os.MkdirAll(m.ConfigDir, 0700))
+
+	kubeconfig := path.Join(m.ConfigDir, "admin.conf")
+
+	local := `clusters:
+- cluster:
+    server: https://10.10.0.5:6443
+  name: mock
+users:
+- name: mock
+  user:
+    client-certificate-data: b2xkLWNlcnQ=
+    client-key-data: a2V5
+`
+
+	renewed := `clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: cluster.local
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: bmV3LWNlcnQ=
+    client-key-data: a2V5
+`
+
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(local), 0600))
+	require.NoError(t, m.refreshKubeconfig([]byte(renewed)))
+
+	out, err := os.ReadFile(kubeconfig)
+	require.NoError(t, err)
+
+	// Only the client certificate is replaced.
+	assert.Contains(t, string(out), "server: https://10.10.0.5:6443")
+	assert.Contains(t, string(out), "client-certificate-data: bmV3LWNlcnQ=")
+	assert.Contains(t, string(out), "client-key-data: a2V5")
+	assert.NotContains(t, string(out), "b2xkLWNlcnQ=")
+}
+
+func TestRefreshKubeconfig_Invalid(t *testing.T) {
+	m := MockManager(t)
+	require.NoError(t, os.MkdirAll(m.ConfigDir, 0700))
+
+	kubeconfig := path.Join(m.ConfigDir, "admin.conf")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte("users: []"), 0600))
+
+	err := m.refreshKubeconfig([]byte("users: []"))
+	assert.EqualError(t, err, "read cluster kubeconfig: kubeconfig does not contain a client certificate")
+}
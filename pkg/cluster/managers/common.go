@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
 	"github.com/MusicDin/kubitect/pkg/models/config"
 	"github.com/MusicDin/kubitect/pkg/models/infra"
 	"github.com/MusicDin/kubitect/pkg/tools/ansible"
@@ -24,6 +26,10 @@ type common struct {
 	InfraConfig       *infra.Config
 
 	Ansible ansible.Ansible
+
+	// Phase (optional) is used to run named phases, so that the progress
+	// of the apply can be recorded.
+	Phase interfaces.PhaseFunc
 }
 
 func (e common) K8sVersion() string {
@@ -38,6 +44,15 @@ func (e common) SshPKey() string {
 	return e.SshPrivateKeyPath
 }
 
+// runPhase runs the given function as a named phase of the apply.
+func (e common) runPhase(name string, fn func() error) error {
+	if e.Phase == nil {
+		return fn()
+	}
+
+	return e.Phase(name, fn)
+}
+
 // mergeKubeconfig merges cluster kubeconfig with config default
 // config in user directory (~/.kube/config). Note that if kubectl
 // is not present locally, the command will fail.
@@ -123,3 +138,60 @@ func extractNewNodes(events []event.Event) ([]config.Instance, error) {
 
 	return nodes, nil
 }
+
+// filterByType returns node instances of the given type (e.g. "master").
+func filterByType(nodes []config.Instance, typeName string) []config.Instance {
+	var filtered []config.Instance
+	for _, n := range nodes {
+		if n.GetTypeName() == typeName {
+			filtered = append(filtered, n)
+		}
+	}
+
+	return filtered
+}
+
+// controlPlaneChanged returns true if the events add or remove control
+// plane nodes.
+func controlPlaneChanged(events []event.Event) (bool, error) {
+	newNodes, err := extractNewNodes(events)
+	if err != nil {
+		return false, err
+	}
+
+	rmNodes, err := extractRemovedNodes(events)
+	if err != nil {
+		return false, err
+	}
+
+	masters := filterByType(append(newNodes, rmNodes...), "master")
+	return len(masters) > 0, nil
+}
+
+// controlPlaneLeader returns a provisioned control plane node that
+// remains part of the cluster and is not added by the given events.
+// Such node can be used to manage the cluster while it is being scaled.
+func (e common) controlPlaneLeader(events []event.Event) (config.MasterInstance, error) {
+	newNodes, err := extractNewNodes(events)
+	if err != nil {
+		return config.MasterInstance{}, err
+	}
+
+	newMasters := filterByType(newNodes, "master")
+
+	for _, i := range e.InfraConfig.Nodes.Master.Instances {
+		remains := slices.ContainsFunc(e.Config.Cluster.Nodes.Master.Instances, func(m config.MasterInstance) bool {
+			return m.Id == i.Id
+		})
+
+		isNew := slices.ContainsFunc(newMasters, func(n config.Instance) bool {
+			return n.GetID() == i.Id
+		})
+
+		if remains && !isNew {
+			return i, nil
+		}
+	}
+
+	return config.MasterInstance{}, fmt.Errorf("no control plane node remains in the cluster")
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
 	"github.com/MusicDin/kubitect/pkg/env"
 	"github.com/MusicDin/kubitect/pkg/models/config"
 	"github.com/MusicDin/kubitect/pkg/models/infra"
@@ -44,6 +45,7 @@ func NewK3sManager(
 	sharedDir string,
 	cfg *config.Config,
 	infraCfg *infra.Config,
+	phase interfaces.PhaseFunc,
 ) *k3s {
 	return &k3s{
 		common: common{
@@ -55,6 +57,7 @@ func NewK3sManager(
 			SharedDir:         sharedDir,
 			Config:            cfg,
 			InfraConfig:       infraCfg,
+			Phase:             phase,
 		},
 		ProjectDir: filepath.Join(clusterPath, "ansible", "k3s"),
 	}
@@ -63,6 +66,27 @@ func NewK3sManager(
 // Init clones k3s project, initializes virtual environment
 // and generates Ansible hosts inventory.
 func (e *k3s) Init() error {
+	venvPath := path.Join(e.SharedDir, "venv", "k3s", env.ConstK3sVersion)
+
+	err := e.runPhase("manager-init", func() error {
+		return e.init(venvPath)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if e.Ansible == nil {
+		ansibleBinDir := path.Join(venvPath, "bin")
+		e.Ansible = ansible.NewAnsible(ansibleBinDir, e.CacheDir)
+	}
+
+	return nil
+}
+
+// init clones k3s project and initializes virtual environment on the
+// given path.
+func (e *k3s) init(venvPath string) error {
 	err := os.RemoveAll(e.ProjectDir)
 	if err != nil {
 		return err
@@ -77,14 +101,10 @@ func (e *k3s) Init() error {
 	if e.Ansible == nil {
 		// Virtual environment.
 		reqPath := filepath.Join(e.ClusterPath, "ansible/kubitect/requirements.txt")
-		venvPath := path.Join(e.SharedDir, "venv", "k3s", env.ConstK3sVersion)
 		err = virtualenv.NewVirtualEnv(venvPath, reqPath).Init()
 		if err != nil {
 			return fmt.Errorf("k3s: initialize virtual environment: %v", err)
 		}
-
-		ansibleBinDir := path.Join(venvPath, "bin")
-		e.Ansible = ansible.NewAnsible(ansibleBinDir, e.CacheDir)
 	}
 
 	return nil
@@ -100,23 +120,28 @@ func (e *k3s) Sync() error {
 		InfraNodes:  e.InfraConfig.Nodes,
 	}
 
-	return NewTemplate("k3s/inventory.yaml", nodes).Write(filepath.Join(e.ConfigDir, "nodes.yaml"))
+	return e.runPhase("manager-sync", func() error {
+		return NewTemplate("k3s/inventory.yaml", nodes).Write(filepath.Join(e.ConfigDir, "nodes.yaml"))
+	})
 }
 
 // Create creates a Kubernetes cluster by calling appropriate k3s
 // playbooks.
 func (e *k3s) Create() error {
-	if err := e.HAProxy(); err != nil {
+	if err := e.runPhase("haproxy", e.HAProxy); err != nil {
 		return err
 	}
 
 	inventory := filepath.Join(e.ConfigDir, "nodes.yaml")
-	err := e.K3sCreate(inventory)
+	err := e.runPhase("create", func() error {
+		return e.K3sCreate(inventory)
+	})
+
 	if err != nil {
 		return err
 	}
 
-	err = e.Finalize()
+	err = e.runPhase("finalize", e.Finalize)
 	if err != nil {
 		return err
 	}
@@ -140,42 +165,92 @@ func (e *k3s) Create() error {
 }
 
 // Upgrades upgrades a Kubernetes cluster by calling appropriate k3s
-// playbooks.
+// playbooks. With the rolling strategy, nodes are upgraded in batches and
+// health of each batch is verified before the next one is upgraded.
 func (e *k3s) Upgrade() error {
-	err := e.K3sUpgrade()
+	var err error
+
+	if e.Config.Kubernetes.Upgrade.Strategy == config.ROLLING {
+		err = e.rollingUpgrade(func(nodes []string) error {
+			return e.K3sUpgrade(nodes...)
+		})
+	} else {
+		err = e.runPhase("upgrade", func() error {
+			return e.K3sUpgrade()
+		})
+	}
+
 	if err != nil {
 		return err
 	}
 
-	return e.Finalize()
+	return e.runPhase("finalize", e.Finalize)
 }
 
-// ScaleUp adds new nodes to the cluster.
+// ScaleUp adds new nodes to the cluster. If control plane nodes have been
+// added or removed, load balancers are updated to reflect the new set of
+// control plane nodes.
 func (e *k3s) ScaleUp(events event.Events) error {
 	newNodes, err := extractNewNodes(events)
 	if err != nil {
 		return err
 	}
 
-	if len(newNodes) == 0 {
-		// No removed nodes.
+	cpChanged, err := controlPlaneChanged(events)
+	if err != nil {
+		return err
+	}
+
+	if len(newNodes) == 0 && !cpChanged {
 		return nil
 	}
 
-	nodes := make(map[string]config.Instance, len(newNodes))
+	err = e.runPhase("haproxy", e.HAProxy)
+	if err != nil {
+		return err
+	}
+
+	values := struct {
+		Leader *config.MasterInstance
+		Nodes  map[string]config.Instance
+	}{
+		Nodes: make(map[string]config.Instance),
+	}
+
 	for _, n := range newNodes {
-		name := fmt.Sprintf("%s-%s-%s", e.ClusterName, n.GetTypeName(), n.GetID())
-		nodes[name] = n
+		if n.GetTypeName() == "lb" {
+			// Load balancers are configured by HAProxy playbook.
+			continue
+		}
+
+		name := e.nodeName(n)
+		values.Nodes[name] = n
+	}
+
+	if len(values.Nodes) == 0 {
+		return nil
+	}
+
+	if len(filterByType(newNodes, "master")) > 0 {
+		// New servers join the cluster through an existing one.
+		leader, err := e.controlPlaneLeader(events)
+		if err != nil {
+			return err
+		}
+
+		values.Leader = &leader
 	}
 
 	inventory := filepath.Join(e.ConfigDir, "nodes_tmp.yaml")
-	err = NewTemplate("k3s/inventory_partial.yaml", nodes).Write(inventory)
+	err = NewTemplate("k3s/inventory_partial.yaml", values).Write(inventory)
 	if err != nil {
 		return err
 	}
 
 	defer os.Remove(inventory)
-	return e.K3sCreate(inventory)
+	return e.runPhase("scale-up", func() error {
+		return e.K3sCreate(inventory)
+	})
 }
 
 // ScaleDown gracefully removes nodes from the cluster.
@@ -190,20 +265,37 @@ func (e *k3s) ScaleDown(events event.Events) error {
 		return nil
 	}
 
-	// Establish connection with one of the master nodes.
-	leader := e.Config.Cluster.Nodes.Master.Instances[0]
+	// Establish connection with one of the remaining master nodes.
+	leader, err := e.controlPlaneLeader(events)
+	if err != nil {
+		return err
+	}
+
+	return e.runPhase("scale-down", func() error {
+		return e.removeNodes(leader, rmNodes)
+	})
+}
+
+// removeNodes drains the given nodes and removes them from the cluster.
+// Commands are executed on the given leader node.
+func (e *k3s) removeNodes(leader config.MasterInstance, rmNodes []config.Instance) error {
 	ssh := exec.NewSSHClient(e.SshUser(), string(leader.IP)).
 		WithPrivateKeyFile(e.SshPKey()).
 		WithSuperUser(true)
 
-	ssh.SetCombinedStdout(os.Stdout)
+	ssh.SetCombinedStdout(ui.Streams().Out().File())
 
 	defer ssh.Close()
 
 	for _, n := range rmNodes {
-		name := fmt.Sprintf("%s-%s-%s", e.ClusterName, n.GetTypeName(), n.GetID())
+		if n.GetTypeName() == "lb" {
+			// Load balancers are not part of the Kubernetes cluster.
+			continue
+		}
 
-		err = ssh.Run("kubectl", "cordon", name)
+		name := e.nodeName(n)
+
+		err := ssh.Run("kubectl", "cordon", name)
 		if err != nil {
 			return fmt.Errorf("cordon node %q: %v", name, err)
 		}
@@ -213,6 +305,15 @@ func (e *k3s) ScaleDown(events event.Events) error {
 			return fmt.Errorf("drain node %q: %v", name, err)
 		}
 
+		if n.GetTypeName() == "master" {
+			// Remove etcd member before the node is deleted, so that
+			// the etcd cluster does not wait for the removed member.
+			err = ssh.Run("kubectl", "annotate", "node", name, "etcd.k3s.cattle.io/remove=true", "--overwrite")
+			if err != nil {
+				return fmt.Errorf("remove etcd member of node %q: %v", name, err)
+			}
+		}
+
 		err = ssh.Run("kubectl", "delete", "node", name)
 		if err != nil {
 			return fmt.Errorf("delete node %q: %v", name, err)
@@ -42,9 +42,10 @@ func (e *k3s) K3sCreate(inventory string) error {
 	return e.Ansible.Exec(pb)
 }
 
-// K3sUpdate function calls an Ansible playbook that configures Kubernetes
-// cluster.
-func (e *k3s) K3sUpgrade() error {
+// K3sUpgrade function calls an Ansible playbook that upgrades Kubernetes
+// nodes to a newer version. If limit is provided, only the given nodes are
+// upgraded.
+func (e *k3s) K3sUpgrade(limit ...string) error {
 	vars := map[string]string{
 		"k3s_version":       fmt.Sprintf("%s+k3s1", e.K8sVersion()),
 		"api_endpoint":      string(e.InfraConfig.Nodes.LoadBalancer.VIP),
@@ -58,6 +59,7 @@ func (e *k3s) K3sUpgrade() error {
 		WorkingDir: e.ProjectDir,
 		Path:       filepath.Join(e.ProjectDir, "playbook/upgrade.yml"),
 		Inventory:  filepath.Join(e.ConfigDir, "nodes.yaml"),
+		Limit:      limit,
 		Become:     true,
 		User:       e.SshUser(),
 		PrivateKey: e.SshPKey(),
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 
 	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
 	"github.com/MusicDin/kubitect/pkg/env"
 	"github.com/MusicDin/kubitect/pkg/models/config"
 	"github.com/MusicDin/kubitect/pkg/models/infra"
@@ -30,6 +32,7 @@ func NewKubesprayManager(
 	sharedDir string,
 	cfg *config.Config,
 	infraCfg *infra.Config,
+	phase interfaces.PhaseFunc,
 ) *kubespray {
 	return &kubespray{
 		common: common{
@@ -41,6 +44,7 @@ func NewKubesprayManager(
 			SharedDir:         sharedDir,
 			Config:            cfg,
 			InfraConfig:       infraCfg,
+			Phase:             phase,
 		},
 	}
 }
@@ -48,6 +52,27 @@ func NewKubesprayManager(
 // Init clones Kubespray project, initializes virtual environment
 // and generates Ansible hosts inventory.
 func (e *kubespray) Init() error {
+	venvPath := filepath.Join(e.SharedDir, "venv", "kubespray", env.ConstKubesprayVersion)
+
+	err := e.runPhase("manager-init", func() error {
+		return e.init(venvPath)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if e.Ansible == nil {
+		ansibleBinDir := path.Join(venvPath, "bin")
+		e.Ansible = ansible.NewAnsible(ansibleBinDir, e.CacheDir)
+	}
+
+	return nil
+}
+
+// init clones Kubespray project and initializes virtual environment
+// on the given path.
+func (e *kubespray) init(venvPath string) error {
 	url := env.ConstKubesprayUrl
 	ver := env.ConstKubesprayVersion
 
@@ -66,14 +91,10 @@ func (e *kubespray) Init() error {
 	if e.Ansible == nil {
 		// Virtual environment.
 		reqPath := filepath.Join(e.ClusterPath, "ansible/kubespray/requirements.txt")
-		venvPath := filepath.Join(e.SharedDir, "venv", "kubespray", env.ConstKubesprayVersion)
 		err = virtualenv.NewVirtualEnv(venvPath, reqPath).Init()
 		if err != nil {
 			return fmt.Errorf("kubespray: initialize virtual environment: %v", err)
 		}
-
-		ansibleBinDir := path.Join(venvPath, "bin")
-		e.Ansible = ansible.NewAnsible(ansibleBinDir, e.CacheDir)
 	}
 
 	return nil
@@ -82,28 +103,30 @@ func (e *kubespray) Init() error {
 // Sync regenerates required Ansible inventories and Kubespray group
 // variables.
 func (e *kubespray) Sync() error {
-	err := e.generateInventory()
-	if err != nil {
-		return err
-	}
+	return e.runPhase("manager-sync", func() error {
+		err := e.generateInventory()
+		if err != nil {
+			return err
+		}
 
-	return e.generateGroupVars()
+		return e.generateGroupVars()
+	})
 }
 
 // Create creates a Kubernetes cluster by calling appropriate Kubespray
 // playbooks.
 func (e *kubespray) Create() error {
-	err := e.HAProxy()
+	err := e.runPhase("haproxy", e.HAProxy)
 	if err != nil {
 		return err
 	}
 
-	err = e.KubesprayCreate()
+	err = e.runPhase("create", e.KubesprayCreate)
 	if err != nil {
 		return err
 	}
 
-	err = e.Finalize()
+	err = e.runPhase("finalize", e.Finalize)
 	if err != nil {
 		return err
 	}
@@ -127,14 +150,26 @@ func (e *kubespray) Create() error {
 }
 
 // Upgrades upgrades a Kubernetes cluster by calling appropriate Kubespray
-// playbooks.
+// playbooks. With the rolling strategy, nodes are upgraded in batches and
+// health of each batch is verified before the next one is upgraded.
 func (e *kubespray) Upgrade() error {
-	err := e.KubesprayUpgrade()
+	var err error
+
+	if e.Config.Kubernetes.Upgrade.Strategy == config.ROLLING {
+		err = e.rollingUpgrade(func(nodes []string) error {
+			return e.KubesprayUpgrade(nodes...)
+		})
+	} else {
+		err = e.runPhase("upgrade", func() error {
+			return e.KubesprayUpgrade()
+		})
+	}
+
 	if err != nil {
 		return err
 	}
 
-	err = e.Finalize()
+	err = e.runPhase("finalize", e.Finalize)
 	if err != nil {
 		return err
 	}
@@ -144,22 +179,46 @@ func (e *kubespray) Upgrade() error {
 	return e.rewriteKubeconfig()
 }
 
-// ScaleUp adds new nodes to the cluster.
+// ScaleUp adds new nodes to the cluster. If control plane nodes have been
+// added or removed, the control plane is reconfigured and load balancers
+// are updated to reflect the new set of control plane nodes.
 func (e *kubespray) ScaleUp(events event.Events) error {
-	events = events.FilterByAction(event.Action_ScaleUp)
-	if len(events) == 0 {
+	newNodes, err := extractNewNodes(events)
+	if err != nil {
+		return err
+	}
+
+	cpChanged, err := controlPlaneChanged(events)
+	if err != nil {
+		return err
+	}
+
+	if len(newNodes) == 0 && !cpChanged {
 		return nil
 	}
 
-	err := e.HAProxy()
+	err = e.runPhase("haproxy", e.HAProxy)
 	if err != nil {
 		return err
 	}
 
-	return e.KubesprayScale()
+	if cpChanged {
+		err = e.runPhase("scale-control-plane", e.KubesprayScaleControlPlane)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(newNodes) == len(filterByType(newNodes, "master")) {
+		// Only control plane nodes have been added.
+		return nil
+	}
+
+	return e.runPhase("scale-up", e.KubesprayScale)
 }
 
-// ScaleDown gracefully removes nodes from the cluster.
+// ScaleDown gracefully removes nodes from the cluster. Kubespray removes
+// etcd members of removed control plane nodes before the nodes themselves.
 func (e *kubespray) ScaleDown(events event.Events) error {
 	rmNodes, err := extractRemovedNodes(events)
 	if err != nil {
@@ -173,7 +232,7 @@ func (e *kubespray) ScaleDown(events event.Events) error {
 
 	var names []string
 	for _, n := range rmNodes {
-		name := fmt.Sprintf("%s-%s-%s", e.ClusterName, n.GetTypeName(), n.GetID())
+		name := e.nodeName(n)
 		names = append(names, name)
 	}
 
@@ -182,7 +241,17 @@ func (e *kubespray) ScaleDown(events event.Events) error {
 		return err
 	}
 
-	err = e.KubesprayRemoveNodes(names)
+	// Kubespray cannot remove the first control plane node. Therefore,
+	// removed nodes are moved to the end of the inventory.
+	err = e.generateInventory(rmNodes...)
+	if err != nil {
+		return err
+	}
+
+	err = e.runPhase("scale-down", func() error {
+		return e.KubesprayRemoveNodes(names)
+	})
+
 	if err != nil {
 		return err
 	}
@@ -191,13 +260,36 @@ func (e *kubespray) ScaleDown(events event.Events) error {
 }
 
 // generateInventory creates an Ansible inventory containing cluster nodes.
-func (e *kubespray) generateInventory() error {
+// Given control plane nodes are placed after the other control plane nodes.
+func (e *kubespray) generateInventory(last ...config.Instance) error {
+	infraNodes := e.InfraConfig.Nodes
+
+	isLast := func(i config.MasterInstance) bool {
+		return slices.ContainsFunc(last, func(n config.Instance) bool {
+			return n.GetTypeName() == i.GetTypeName() && n.GetID() == i.GetID()
+		})
+	}
+
+	masters := slices.Clone(infraNodes.Master.Instances)
+	slices.SortStableFunc(masters, func(a, b config.MasterInstance) int {
+		switch {
+		case isLast(a) == isLast(b):
+			return 0
+		case isLast(a):
+			return 1
+		default:
+			return -1
+		}
+	})
+
+	infraNodes.Master.Instances = masters
+
 	nodes := struct {
 		ConfigNodes config.Nodes
 		InfraNodes  config.Nodes
 	}{
 		ConfigNodes: e.Config.Cluster.Nodes,
-		InfraNodes:  e.InfraConfig.Nodes,
+		InfraNodes:  infraNodes,
 	}
 
 	return NewTemplate("kubespray/inventory.yaml", nodes).Write(filepath.Join(e.ConfigDir, "nodes.yaml"))
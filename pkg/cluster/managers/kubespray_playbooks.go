@@ -28,8 +28,9 @@ func (e *kubespray) KubesprayCreate() error {
 }
 
 // KubesprayUpgrade function calls an Ansible playbook that upgrades Kubernetes
-// nodes to a newer version.
-func (e *kubespray) KubesprayUpgrade() error {
+// nodes to a newer version. If limit is provided, only the given nodes are
+// upgraded.
+func (e *kubespray) KubesprayUpgrade(limit ...string) error {
 	vars := map[string]string{
 		"kube_version": e.K8sVersion(),
 	}
@@ -37,6 +38,7 @@ func (e *kubespray) KubesprayUpgrade() error {
 	pb := ansible.Playbook{
 		Path:       filepath.Join(e.ClusterPath, "ansible/kubespray/upgrade-cluster.yml"),
 		Inventory:  filepath.Join(e.ClusterPath, "config/nodes.yaml"),
+		Limit:      limit,
 		Become:     true,
 		User:       e.SshUser(),
 		PrivateKey: e.SshPKey(),
@@ -67,6 +69,38 @@ func (e *kubespray) KubesprayScale() error {
 	return e.Ansible.Exec(pb)
 }
 
+// KubesprayScaleControlPlane function calls Ansible playbooks that join new
+// control plane nodes to the etcd cluster and the control plane, and
+// reconfigure the remaining control plane nodes after some have been added
+// or removed. As required by Kubespray, the cluster playbook is used (scale
+// playbook does not support control plane nodes), followed by the upgrade
+// playbook that updates etcd configuration on all control plane nodes.
+func (e *kubespray) KubesprayScaleControlPlane() error {
+	vars := map[string]string{
+		"kube_version":         e.K8sVersion(),
+		"ignore_assert_errors": "yes",
+	}
+
+	for _, playbook := range []string{"cluster.yml", "upgrade-cluster.yml"} {
+		pb := ansible.Playbook{
+			Path:       filepath.Join(e.ClusterPath, "ansible/kubespray", playbook),
+			Inventory:  filepath.Join(e.ClusterPath, "config/nodes.yaml"),
+			Limit:      []string{"etcd", "kube_control_plane"},
+			Become:     true,
+			User:       e.SshUser(),
+			PrivateKey: e.SshPKey(),
+			Timeout:    3000,
+			ExtraVars:  vars,
+		}
+
+		if err := e.Ansible.Exec(pb); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // KubesprayRemoveNodes function calls an Ansible playbook that removes the nodes with
 // the provided names.
 func (e *kubespray) KubesprayRemoveNodes(removedNodeNames []string) error {
@@ -2,6 +2,7 @@ package managers
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"reflect"
 	"testing"
@@ -83,6 +84,7 @@ func TestNewManager(t *testing.T) {
 		path.Join(tmpDir, "share"),
 		&config.Config{},
 		&infra.Config{},
+		nil,
 	)
 	assert.NotNil(t, e)
 }
@@ -138,3 +140,72 @@ func TestScaleUp_NoEvents(t *testing.T) {
 	err := MockManager(t).ScaleUp(nil)
 	assert.NoError(t, err)
 }
+
+func TestScaleUp_ControlPlane(t *testing.T) {
+	m := config.MasterInstance{
+		Id: "master",
+	}
+
+	events := MockEvents(t, m, event.Action_ScaleUp)
+	err := MockManager(t).ScaleUp(events)
+	assert.NoError(t, err)
+}
+
+func TestScaleUp_ControlPlaneRemoved(t *testing.T) {
+	e := MockManager(t)
+	e.Ansible = &invalidAnsibleMock{}
+
+	// Control plane is reconfigured after control plane node removal.
+	events := MockEvents(t, config.MasterInstance{Id: "master"}, event.Action_ScaleDown)
+	assert.EqualError(t, e.ScaleUp(events), "error")
+
+	events = MockEvents(t, config.WorkerInstance{Id: "worker"}, event.Action_ScaleDown)
+	assert.NoError(t, e.ScaleUp(events))
+}
+
+func TestGenerateInventory_RemovedMasterLast(t *testing.T) {
+	e := MockManager(t)
+	e.InfraConfig.Nodes = config.MockNodes(t)
+	e.Config.Cluster.Nodes = config.MockNodes(t)
+
+	require.NoError(t, e.generateInventory(e.InfraConfig.Nodes.Master.Instances[0]))
+
+	inv, err := os.ReadFile(path.Join(e.ConfigDir, "nodes.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(inv), "etcd:\n      hosts:\n        cls-master-2:\n        cls-master-3:\n        cls-master-1:\n")
+
+	// Infrastructure configuration is not modified.
+	assert.Equal(t, "cls-master-1", e.InfraConfig.Nodes.Master.Instances[0].Name)
+}
+
+func TestControlPlaneChanged(t *testing.T) {
+	changed, err := controlPlaneChanged(MockEvents(t, config.MasterInstance{}, event.Action_ScaleDown))
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = controlPlaneChanged(MockEvents(t, config.WorkerInstance{}, event.Action_ScaleUp))
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestControlPlaneLeader(t *testing.T) {
+	e := MockManager(t)
+	e.InfraConfig.Nodes = config.MockNodes(t)
+
+	// Master 1 is removed and master 4 is added.
+	e.Config.Cluster.Nodes = config.MockNodes(t)
+	e.Config.Cluster.Nodes.Master.Instances = append(e.Config.Cluster.Nodes.Master.Instances[1:], config.MasterInstance{Id: "4"})
+	e.InfraConfig.Nodes.Master.Instances = append(e.InfraConfig.Nodes.Master.Instances, config.MasterInstance{Id: "4"})
+
+	events := MockEvents(t, config.MasterInstance{Id: "4"}, event.Action_ScaleUp)
+
+	leader, err := e.controlPlaneLeader(events)
+	require.NoError(t, err)
+	assert.Equal(t, "cls-master-2", leader.Name)
+	assert.Equal(t, config.IPv4("192.168.113.12"), leader.IP)
+}
+
+func TestControlPlaneLeader_None(t *testing.T) {
+	_, err := MockManager(t).controlPlaneLeader(nil)
+	assert.EqualError(t, err, "no control plane node remains in the cluster")
+}
@@ -0,0 +1,164 @@
+package managers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+var (
+	// Maximum time to wait for the node to become ready, and the
+	// interval in which its readiness is checked.
+	nodeReadyTimeout  = 10 * time.Minute
+	nodeReadyInterval = 10 * time.Second
+)
+
+// nodeName returns the name of the given node within the Kubernetes
+// cluster.
+func (e common) nodeName(n config.Instance) string {
+	return fmt.Sprintf("%s-%s-%s", e.ClusterName, n.GetTypeName(), n.GetID())
+}
+
+// kubectl runs kubectl command locally against the cluster using the
+// cluster's kubeconfig. Note that if kubectl is not present locally, the
+// command will fail.
+func (e common) kubectl(args ...string) error {
+	c := exec.NewLocalClient()
+	c.SetEnv("KUBECONFIG", filepath.Join(e.ConfigDir, "admin.conf"))
+	c.SetCombinedStdout(ui.Streams().Out().File())
+
+	return c.Run("kubectl", args...)
+}
+
+// kubectlOutput runs kubectl command locally against the cluster and
+// returns its trimmed standard output.
+func (e common) kubectlOutput(args ...string) (string, error) {
+	c := exec.NewLocalClient()
+	c.SetEnv("KUBECONFIG", filepath.Join(e.ConfigDir, "admin.conf"))
+
+	out, err := c.Output("kubectl", args...)
+	if err != nil {
+		return "", fmt.Errorf("kubectl %s: %v", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CordonNode marks the given node as unschedulable. Load balancers are
+// not part of the Kubernetes cluster and are skipped.
+func (e common) CordonNode(n config.Instance) error {
+	if n.GetTypeName() == "lb" {
+		return nil
+	}
+
+	name := e.nodeName(n)
+
+	ui.Printf(ui.INFO, "Cordoning node %q...\n", name)
+
+	err := e.kubectl("cordon", name)
+	if err != nil {
+		return fmt.Errorf("cordon node %q: %v", name, err)
+	}
+
+	return nil
+}
+
+// DrainNode marks the given node as unschedulable and evicts its pods.
+// Load balancers are not part of the Kubernetes cluster and are skipped.
+func (e common) DrainNode(n config.Instance, o interfaces.DrainOptions) error {
+	if n.GetTypeName() == "lb" {
+		return nil
+	}
+
+	name := e.nodeName(n)
+
+	ui.Printf(ui.INFO, "Draining node %q...\n", name)
+
+	err := e.kubectl(drainArgs(name, o)...)
+	if err != nil {
+		return fmt.Errorf("drain node %q: %v", name, err)
+	}
+
+	return nil
+}
+
+// drainArgs returns kubectl arguments that drain the node with the given
+// name according to the drain options.
+func drainArgs(name string, o interfaces.DrainOptions) []string {
+	args := []string{"drain", name, "--ignore-daemonsets"}
+
+	if o.DeleteEmptyDirData {
+		args = append(args, "--delete-emptydir-data")
+	}
+
+	if o.Force {
+		args = append(args, "--force")
+	}
+
+	if o.DisableEviction {
+		args = append(args, "--disable-eviction")
+	}
+
+	if o.GracePeriod > 0 {
+		args = append(args, fmt.Sprintf("--grace-period=%d", int(o.GracePeriod.Seconds())))
+	}
+
+	if o.Timeout > 0 {
+		args = append(args, fmt.Sprintf("--timeout=%s", o.Timeout))
+	}
+
+	return args
+}
+
+// UncordonNode waits for the given node to become ready and marks it as
+// schedulable. Load balancers are not part of the Kubernetes cluster and
+// are skipped.
+func (e common) UncordonNode(n config.Instance) error {
+	if n.GetTypeName() == "lb" {
+		return nil
+	}
+
+	name := e.nodeName(n)
+
+	if err := e.waitNodeReady(name); err != nil {
+		return err
+	}
+
+	ui.Printf(ui.INFO, "Uncordoning node %q...\n", name)
+
+	err := e.kubectl("uncordon", name)
+	if err != nil {
+		return fmt.Errorf("uncordon node %q: %v", name, err)
+	}
+
+	return nil
+}
+
+// waitNodeReady waits until the node with the given name reports that
+// it is ready. Errors are tolerated until the timeout expires, since the
+// API server may be unavailable while the node is restarting.
+func (e common) waitNodeReady(name string) error {
+	ui.Printf(ui.INFO, "Waiting for node %q to become ready...\n", name)
+
+	deadline := time.Now().Add(nodeReadyTimeout)
+	timeout := fmt.Sprintf("--timeout=%s", nodeReadyInterval)
+
+	for {
+		err := e.kubectl("wait", "--for=condition=Ready", "node/"+name, timeout)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %q has not become ready within %s: %v", name, nodeReadyTimeout, err)
+		}
+
+		time.Sleep(nodeReadyInterval)
+	}
+}
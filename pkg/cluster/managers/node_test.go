@@ -0,0 +1,32 @@
+package managers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainArgs(t *testing.T) {
+	args := drainArgs("node", interfaces.DrainOptions{})
+	assert.Equal(t, []string{"drain", "node", "--ignore-daemonsets"}, args)
+
+	args = drainArgs("node", interfaces.DrainOptions{
+		Timeout:            5 * time.Minute,
+		GracePeriod:        30 * time.Second,
+		DisableEviction:    true,
+		DeleteEmptyDirData: true,
+		Force:              true,
+	})
+
+	assert.Equal(t, []string{
+		"drain", "node", "--ignore-daemonsets",
+		"--delete-emptydir-data",
+		"--force",
+		"--disable-eviction",
+		"--grace-period=30",
+		"--timeout=5m0s",
+	}, args)
+}
@@ -177,3 +177,40 @@ func TestTemplate_Inventory_NoWorkers(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, expect, pop)
 }
+
+func TestK3sTemplate_InventoryPartial(t *testing.T) {
+	nodes := config.MockNodes(t)
+
+	values := struct {
+		Leader *config.MasterInstance
+		Nodes  map[string]config.Instance
+	}{
+		Leader: &nodes.Master.Instances[0],
+		Nodes: map[string]config.Instance{
+			"cls-master-4": config.MasterInstance{Id: "4", IP: "192.168.113.14"},
+			"cls-worker-4": config.WorkerInstance{Id: "4", IP: "192.168.113.24"},
+		},
+	}
+
+	tpl := NewTemplate("k3s/inventory_partial.yaml", values)
+	pop, err := template.Populate(tpl)
+
+	expect := template.TrimTemplate(`
+		---
+		k3s_cluster:
+			children:
+				server:
+					hosts:
+						cls-master-1:
+							ansible_host: 192.168.113.11
+						cls-master-4:
+							ansible_host: 192.168.113.14
+				agent:
+					hosts:
+						cls-worker-4:
+							ansible_host: 192.168.113.24
+	`)
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, pop)
+}
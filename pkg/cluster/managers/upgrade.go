@@ -0,0 +1,158 @@
+package managers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
+
+var (
+	// Maximum time to wait for the upgraded node to become healthy, and
+	// the interval in which its health is checked.
+	nodeHealthTimeout  = 10 * time.Minute
+	nodeHealthInterval = 10 * time.Second
+)
+
+// upgradeBatches splits cluster nodes into batches of nodes that are
+// upgraded at the same time. Master nodes are upgraded before worker
+// nodes, each in batches of the configured size.
+func (e common) upgradeBatches() [][]string {
+	nodes := e.Config.Cluster.Nodes
+	strategy := e.Config.Kubernetes.Upgrade
+
+	var masters []string
+	for _, n := range nodes.Master.Instances {
+		masters = append(masters, e.nodeName(n))
+	}
+
+	var workers []string
+	for _, n := range nodes.Worker.Instances {
+		workers = append(workers, e.nodeName(n))
+	}
+
+	batches := batch(masters, int(strategy.MasterBatchSize))
+	return append(batches, batch(workers, int(strategy.WorkerBatchSize))...)
+}
+
+// batch splits the given items into batches of the given size.
+func batch(items []string, size int) [][]string {
+	size = max(size, 1)
+
+	var batches [][]string
+	for size < len(items) {
+		items, batches = items[size:], append(batches, items[:size])
+	}
+
+	if len(items) > 0 {
+		batches = append(batches, items)
+	}
+
+	return batches
+}
+
+// rollingUpgrade upgrades the cluster nodes batch by batch using the given
+// upgrade function, which receives names of the nodes in the batch. Once
+// the batch is upgraded, health of its nodes is verified before the next
+// batch is upgraded. If any node is unhealthy, the upgrade stops and the
+// remaining nodes are left untouched.
+func (e common) rollingUpgrade(upgrade func(nodes []string) error) error {
+	batches := e.upgradeBatches()
+
+	for i, b := range batches {
+		err := e.runPhase(fmt.Sprintf("upgrade-batch-%d", i+1), func() error {
+			ui.Printf(ui.INFO, "Upgrading nodes %s (batch %d/%d)...\n", strings.Join(b, ", "), i+1, len(batches))
+
+			if err := upgrade(b); err != nil {
+				return err
+			}
+
+			for _, name := range b {
+				if err := e.waitNodeHealthy(name); err != nil {
+					return fmt.Errorf("upgrade stopped at batch %d/%d: node %q is not healthy: %v", i+1, len(batches), name, err)
+				}
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitNodeHealthy waits until the node with the given name is healthy.
+func (e common) waitNodeHealthy(name string) error {
+	ui.Printf(ui.INFO, "Verifying health of node %q...\n", name)
+
+	deadline := time.Now().Add(nodeHealthTimeout)
+
+	for {
+		err := e.checkNodeHealth(name)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(nodeHealthInterval)
+	}
+}
+
+// checkNodeHealth verifies that the node with the given name is ready,
+// runs the kubelet of the desired Kubernetes version, and that all system
+// pods on the node are running.
+func (e common) checkNodeHealth(name string) error {
+	ready, err := e.kubectlOutput("get", "node", name, "-o", `jsonpath={.status.conditions[?(@.type=="Ready")].status}`)
+	if err != nil {
+		return err
+	}
+
+	version, err := e.kubectlOutput("get", "node", name, "-o", "jsonpath={.status.nodeInfo.kubeletVersion}")
+	if err != nil {
+		return err
+	}
+
+	pods, err := e.kubectlOutput("get", "pods", "-n", "kube-system", "--field-selector", "spec.nodeName="+name, "-o", `jsonpath={range .items[*]}{.metadata.name} {.status.phase}{"\n"}{end}`)
+	if err != nil {
+		return err
+	}
+
+	return nodeHealth(ready, version, pods, e.K8sVersion())
+}
+
+// nodeHealth returns an error describing why the node is not healthy.
+// It expects the status of the node's ready condition, the version of the
+// node's kubelet and a list of system pods on the node, where each line
+// contains pod's name and phase.
+func nodeHealth(ready string, kubeletVersion string, pods string, version string) error {
+	if ready != "True" {
+		return fmt.Errorf("node is not ready")
+	}
+
+	// Strip the distribution suffix, for example "+k3s1".
+	kubeletVersion, _, _ = strings.Cut(kubeletVersion, "+")
+	if kubeletVersion != version {
+		return fmt.Errorf("kubelet version is %s instead of %s", kubeletVersion, version)
+	}
+
+	var notRunning []string
+	for _, line := range strings.Split(pods, "\n") {
+		pod, phase, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if pod != "" && phase != "Running" && phase != "Succeeded" {
+			notRunning = append(notRunning, fmt.Sprintf("%s (%s)", pod, phase))
+		}
+	}
+
+	if len(notRunning) > 0 {
+		return fmt.Errorf("system pods are not running: %s", strings.Join(notRunning, ", "))
+	}
+
+	return nil
+}
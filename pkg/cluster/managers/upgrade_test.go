@@ -0,0 +1,78 @@
+package managers
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, batch(items, 2))
+	assert.Equal(t, [][]string{{"a", "b", "c", "d", "e"}}, batch(items, 10))
+	assert.Equal(t, [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}, batch(items, 0))
+	assert.Empty(t, batch(nil, 2))
+}
+
+func TestUpgradeBatches(t *testing.T) {
+	m := MockManager(t)
+
+	nodes := &m.Config.Cluster.Nodes
+	nodes.Master.Instances = []config.MasterInstance{{Id: "1"}, {Id: "2"}, {Id: "3"}}
+	nodes.Worker.Instances = []config.WorkerInstance{{Id: "1"}, {Id: "2"}}
+
+	m.Config.Kubernetes.Upgrade = config.Upgrade{
+		MasterBatchSize: 1,
+		WorkerBatchSize: 2,
+	}
+
+	expect := [][]string{
+		{"mock-master-1"},
+		{"mock-master-2"},
+		{"mock-master-3"},
+		{"mock-worker-1", "mock-worker-2"},
+	}
+
+	assert.Equal(t, expect, m.upgradeBatches())
+}
+
+func TestRollingUpgrade_StopsOnUnhealthyNode(t *testing.T) {
+	m := MockManager(t)
+
+	nodes := &m.Config.Cluster.Nodes
+	nodes.Master.Instances = []config.MasterInstance{{Id: "1"}}
+	nodes.Worker.Instances = []config.WorkerInstance{{Id: "1"}}
+
+	tmp := nodeHealthTimeout
+	nodeHealthTimeout = 0
+	defer func() { nodeHealthTimeout = tmp }()
+
+	var upgraded [][]string
+	err := m.rollingUpgrade(func(nodes []string) error {
+		upgraded = append(upgraded, nodes)
+		return nil
+	})
+
+	// Health of the node cannot be verified, because the cluster
+	// does not exist.
+	require.ErrorContains(t, err, `upgrade stopped at batch 1/2: node "mock-master-1" is not healthy`)
+	assert.Equal(t, [][]string{{"mock-master-1"}}, upgraded)
+}
+
+func TestNodeHealth(t *testing.T) {
+	version := "v1.28.6"
+	pods := "kube-proxy-abc Running\ncoredns-xyz Running\njob-1 Succeeded"
+
+	assert.NoError(t, nodeHealth("True", "v1.28.6", pods, version))
+	assert.NoError(t, nodeHealth("True", "v1.28.6+k3s1", "", version))
+
+	assert.EqualError(t, nodeHealth("False", "v1.28.6", pods, version), "node is not ready")
+	assert.EqualError(t, nodeHealth("True", "v1.27.10", pods, version), "kubelet version is v1.27.10 instead of v1.28.6")
+
+	pods = "kube-proxy-abc Running\ncoredns-xyz Pending"
+	assert.EqualError(t, nodeHealth("True", "v1.28.6", pods, version), "system pods are not running: coredns-xyz (Pending)")
+}
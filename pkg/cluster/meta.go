@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"path/filepath"
+	"strconv"
 
 	"github.com/MusicDin/kubitect/pkg/app"
 	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
@@ -14,13 +15,20 @@ const (
 	DefaultConfigDir    = "config"
 	DefaultCacheDir     = "cache"
 	DefaultTerraformDir = DefaultConfigDir + "/terraform"
+	DefaultHistoryDir   = DefaultConfigDir + "/history"
+	DefaultBackupDir    = "backups"
 
 	DefaultNewConfigFilename     = "kubitect.yaml"
 	DefaultAppliedConfigFilename = "kubitect-applied.yaml"
 	DefaultInfraConfigFilename   = "infrastructure.yaml"
+	DefaultCheckpointFilename    = "apply-checkpoint.yaml"
+	DefaultRevisionFilename      = "revision.yaml"
+	DefaultBackupFilename        = "backup.yaml"
+	DefaultSnapshotFilename      = "snapshot.db"
 
 	DefaultTerraformStateFilename = "terraform.tfstate"
 	DefaultKubeconfigFilename     = "admin.conf"
+	DefaultLockFilename           = "kubitect.lock"
 )
 
 type ClusterMeta struct {
@@ -55,6 +63,38 @@ func (c ClusterMeta) InfrastructureConfigPath() string {
 	return filepath.Join(c.ConfigDir(), DefaultInfraConfigFilename)
 }
 
+func (c ClusterMeta) LockPath() string {
+	return filepath.Join(c.Path, DefaultLockFilename)
+}
+
+func (c ClusterMeta) CheckpointPath() string {
+	return filepath.Join(c.ConfigDir(), DefaultCheckpointFilename)
+}
+
+func (c ClusterMeta) HistoryDir() string {
+	return filepath.Join(c.Path, DefaultHistoryDir)
+}
+
+func (c ClusterMeta) RevisionPath(n int) string {
+	return filepath.Join(c.HistoryDir(), strconv.Itoa(n), DefaultRevisionFilename)
+}
+
+func (c ClusterMeta) RevisionConfigPath(n int) string {
+	return filepath.Join(c.HistoryDir(), strconv.Itoa(n), DefaultNewConfigFilename)
+}
+
+func (c ClusterMeta) BackupDir() string {
+	return filepath.Join(c.Path, DefaultBackupDir)
+}
+
+func (c ClusterMeta) BackupPath(name string) string {
+	return filepath.Join(c.BackupDir(), name, DefaultBackupFilename)
+}
+
+func (c ClusterMeta) BackupSnapshotPath(name string) string {
+	return filepath.Join(c.BackupDir(), name, DefaultSnapshotFilename)
+}
+
 func (c ClusterMeta) TfStatePath() string {
 	return filepath.Join(c.Path, DefaultTerraformDir, DefaultTerraformStateFilename)
 }
@@ -67,6 +107,10 @@ func (c ClusterMeta) PrivateSshKeyPath() string {
 	return filepath.Join(c.ConfigDir(), ".ssh", "id_rsa")
 }
 
+func (c ClusterMeta) PendingSshKeyDir() string {
+	return filepath.Join(c.ConfigDir(), ".ssh", "pending")
+}
+
 func (c ClusterMeta) ContainsAppliedConfig() bool {
 	return file.Exists(c.AppliedConfigPath())
 }
@@ -79,6 +123,30 @@ func (c ClusterMeta) ContainsKubeconfig() bool {
 	return file.Exists(c.KubeconfigPath())
 }
 
+// MetaSummary is a serializable representation of the cluster metadata.
+type MetaSummary struct {
+	Name   string `json:"name" yaml:"name"`
+	Path   string `json:"path" yaml:"path"`
+	Local  bool   `json:"local" yaml:"local"`
+	Active bool   `json:"active" yaml:"active"`
+
+	HasAppliedConfig bool `json:"hasAppliedConfig" yaml:"hasAppliedConfig"`
+	HasKubeconfig    bool `json:"hasKubeconfig" yaml:"hasKubeconfig"`
+}
+
+// Summary returns a serializable representation of the cluster metadata.
+// Cluster is considered active if it contains a Terraform state file.
+func (c ClusterMeta) Summary() MetaSummary {
+	return MetaSummary{
+		Name:             c.Name,
+		Path:             c.Path,
+		Local:            c.Local,
+		Active:           c.ContainsTfStateConfig(),
+		HasAppliedConfig: c.ContainsAppliedConfig(),
+		HasKubeconfig:    c.ContainsKubeconfig(),
+	}
+}
+
 func (c *ClusterMeta) Provisioner() provisioner.Provisioner {
 	if c.prov != nil {
 		return c.prov
@@ -0,0 +1,203 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// nodeName returns the name of the given node, which is composed of the
+// cluster name, node type and node ID (for example, "lake-worker-1").
+func (c *Cluster) nodeName(n config.Instance) string {
+	return fmt.Sprintf("%s-%s-%s", c.Name, n.GetTypeName(), n.GetID())
+}
+
+// NodeNames returns names of the provisioned nodes without the cluster
+// name prefix (for example, "worker-1").
+func (c *Cluster) NodeNames() []string {
+	var names []string
+	for _, n := range c.InfraConfig.Nodes.Instances() {
+		names = append(names, fmt.Sprintf("%s-%s", n.GetTypeName(), n.GetID()))
+	}
+
+	return names
+}
+
+// FindNode returns the provisioned node with the given name. The name may
+// be provided either with or without the cluster name prefix (for example,
+// "lake-worker-1" or "worker-1").
+func (c *Cluster) FindNode(name string) (config.Instance, error) {
+	short := strings.TrimPrefix(name, c.Name+"-")
+
+	for _, n := range c.InfraConfig.Nodes.Instances() {
+		if fmt.Sprintf("%s-%s", n.GetTypeName(), n.GetID()) == short {
+			return n, nil
+		}
+	}
+
+	return nil, fmt.Errorf("node %q does not exist in cluster %q (available nodes: %s)", name, c.Name, strings.Join(c.NodeNames(), ", "))
+}
+
+// configNode returns the node from the new configuration that corresponds
+// to the given provisioned node, or nil if such node is not configured.
+func (c *Cluster) configNode(n config.Instance) config.Instance {
+	for _, cn := range c.NewConfig.Cluster.Nodes.Instances() {
+		if cn.GetTypeName() == n.GetTypeName() && cn.GetID() == n.GetID() {
+			return cn
+		}
+	}
+
+	return nil
+}
+
+// nodeHost returns the name of the host on which the given node is
+// deployed. If the node does not specify a host, the default host is
+// returned.
+func (c *Cluster) nodeHost(n config.Instance) string {
+	if cn := c.configNode(n); cn != nil && cn.GetHost() != "" {
+		return cn.GetHost()
+	}
+
+	for _, h := range c.NewConfig.Hosts {
+		if h.Default {
+			return h.Name
+		}
+	}
+
+	return ""
+}
+
+// nodeLabels returns labels of the given node, including the default
+// labels of its node type. Load balancer nodes have no labels.
+func (c *Cluster) nodeLabels(n config.Instance) config.Labels {
+	labels := make(config.Labels)
+
+	nodes := c.NewConfig.Cluster.Nodes
+	switch cn := c.configNode(n).(type) {
+	case config.MasterInstance:
+		maps.Copy(labels, nodes.Master.Default.Labels)
+		maps.Copy(labels, cn.Labels)
+	case config.WorkerInstance:
+		maps.Copy(labels, nodes.Worker.Default.Labels)
+		maps.Copy(labels, cn.Labels)
+	}
+
+	return labels
+}
+
+// NodeSelector selects cluster nodes whose property matches the value.
+// Supported keys are:
+//   - role: node type (master, worker, lb),
+//   - id: node ID,
+//   - name: node name with or without the cluster name prefix,
+//   - host: name of the host on which the node is deployed,
+//   - label.<name>: value of the node label.
+type NodeSelector struct {
+	Key   string
+	Value string
+}
+
+func (s NodeSelector) String() string {
+	return fmt.Sprintf("%s=%s", s.Key, s.Value)
+}
+
+// ParseNodeSelector parses the node selector in format "key=value".
+func ParseNodeSelector(s string) (NodeSelector, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" || value == "" {
+		return NodeSelector{}, fmt.Errorf("invalid node selector %q (expected format: key=value)", s)
+	}
+
+	sel := NodeSelector{
+		Key:   strings.TrimSpace(key),
+		Value: strings.TrimSpace(value),
+	}
+
+	switch {
+	case sel.Key == "role":
+		roles := []string{"master", "worker", "lb"}
+		if !slices.Contains(roles, sel.Value) {
+			return sel, fmt.Errorf("invalid node role %q (valid roles: %s)", sel.Value, strings.Join(roles, ", "))
+		}
+	case sel.Key == "id", sel.Key == "name", sel.Key == "host":
+	case strings.HasPrefix(sel.Key, "label.") && len(sel.Key) > len("label."):
+	default:
+		return sel, fmt.Errorf("invalid node selector key %q (valid keys: role, id, name, host, label.<name>)", sel.Key)
+	}
+
+	return sel, nil
+}
+
+// matches returns true if the given node matches the selector.
+func (s NodeSelector) matches(c *Cluster, n config.Instance) bool {
+	switch s.Key {
+	case "role":
+		return n.GetTypeName() == s.Value
+	case "id":
+		return n.GetID() == s.Value
+	case "name":
+		short := fmt.Sprintf("%s-%s", n.GetTypeName(), n.GetID())
+		return s.Value == short || s.Value == c.nodeName(n)
+	case "host":
+		return c.nodeHost(n) == s.Value
+	}
+
+	label, _ := strings.CutPrefix(s.Key, "label.")
+	value, ok := c.nodeLabels(n)[label]
+
+	return ok && value == s.Value
+}
+
+// SelectNodes returns provisioned nodes that match the given selectors.
+// Selectors with the same key are combined with a logical OR, while
+// selectors with different keys are combined with a logical AND. For
+// example, selectors "role=worker", "id=1" and "id=2" select worker nodes
+// with ID 1 or 2. If no selector is given, all nodes are returned.
+func (c *Cluster) SelectNodes(selectors []NodeSelector) []config.Instance {
+	var nodes []config.Instance
+
+	for _, n := range c.InfraConfig.Nodes.Instances() {
+		matched := make(map[string]bool)
+		for _, s := range selectors {
+			matched[s.Key] = matched[s.Key] || s.matches(c, n)
+		}
+
+		selected := true
+		for _, m := range matched {
+			selected = selected && m
+		}
+
+		if selected {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// Shell opens an interactive shell on the node with the given name. The
+// node is accessed over SSH as the user configured in the node template,
+// using the cluster's SSH key.
+func (c *Cluster) Shell(name string) error {
+	n, err := c.FindNode(name)
+	if err != nil {
+		return err
+	}
+
+	ssh := exec.NewSSHClient(string(c.NewConfig.Cluster.NodeTemplate.User), string(n.GetIP())).
+		WithPrivateKeyFile(c.PrivateSshKeyPath())
+
+	ssh.SetStdin(ui.Streams().In().File())
+	ssh.SetStdout(ui.Streams().Out().File())
+	ssh.SetStderr(ui.Streams().Err().File())
+
+	defer ssh.Close()
+
+	return ssh.Shell(context.Background())
+}
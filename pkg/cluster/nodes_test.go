@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeNames(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	assert.Equal(t, []string{"master-1", "worker-1", "lb-1", "lb-2"}, c.NodeNames())
+}
+
+func TestFindNode(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	n, err := c.FindNode("worker-1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.0.20", string(n.GetIP()))
+
+	n, err = c.FindNode(c.Name + "-lb-2")
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.0.31", string(n.GetIP()))
+}
+
+func TestFindNode_Invalid(t *testing.T) {
+	c := mockStatusCluster(t)
+
+	_, err := c.FindNode("worker-2")
+	assert.ErrorContains(t, err, `node "worker-2" does not exist in cluster "cluster-mock"`)
+	assert.ErrorContains(t, err, "worker-1")
+}
+
+func mockSelectorCluster(t *testing.T) *ClusterMock {
+	c := mockStatusCluster(t)
+
+	c.NewConfig.Hosts = []config.Host{
+		{Name: "host1", Default: true},
+		{Name: "host2"},
+	}
+
+	c.NewConfig.Cluster.Nodes = config.Nodes{
+		Master: config.Master{
+			Default:   config.MasterDefault{Labels: config.Labels{"tier": "control"}},
+			Instances: []config.MasterInstance{{Id: "1"}},
+		},
+		Worker: config.Worker{
+			Default: config.WorkerDefault{Labels: config.Labels{"tier": "app"}},
+			Instances: []config.WorkerInstance{
+				{Id: "1", Host: "host2", Labels: config.Labels{"tier": "storage"}},
+			},
+		},
+		LoadBalancer: config.LB{
+			Instances: []config.LBInstance{{Id: "1"}, {Id: "2", Host: "host2"}},
+		},
+	}
+
+	return c
+}
+
+func TestSelectNodes(t *testing.T) {
+	c := mockSelectorCluster(t)
+
+	tests := []struct {
+		selectors []string
+		expect    []string
+	}{
+		{nil, []string{"master-1", "worker-1", "lb-1", "lb-2"}},
+		{[]string{"role=worker"}, []string{"worker-1"}},
+		{[]string{"role=master", "role=lb"}, []string{"master-1", "lb-1", "lb-2"}},
+		{[]string{"id=1"}, []string{"master-1", "worker-1", "lb-1"}},
+		{[]string{"role=lb", "id=2"}, []string{"lb-2"}},
+		{[]string{"name=worker-1", "name=" + c.Name + "-lb-1"}, []string{"worker-1", "lb-1"}},
+		{[]string{"host=host1"}, []string{"master-1", "lb-1"}},
+		{[]string{"host=host2", "role=lb"}, []string{"lb-2"}},
+		{[]string{"label.tier=control"}, []string{"master-1"}},
+		{[]string{"label.tier=storage"}, []string{"worker-1"}},
+		{[]string{"label.tier=app"}, nil},
+		{[]string{"label.missing=value"}, nil},
+	}
+
+	for _, test := range tests {
+		var selectors []NodeSelector
+		for _, s := range test.selectors {
+			sel, err := ParseNodeSelector(s)
+			require.NoError(t, err)
+
+			selectors = append(selectors, sel)
+		}
+
+		var names []string
+		for _, n := range c.SelectNodes(selectors) {
+			names = append(names, fmt.Sprintf("%s-%s", n.GetTypeName(), n.GetID()))
+		}
+
+		assert.Equal(t, test.expect, names, "selectors: %v", test.selectors)
+	}
+}
+
+func TestParseNodeSelector_Invalid(t *testing.T) {
+	_, err := ParseNodeSelector("worker")
+	assert.EqualError(t, err, `invalid node selector "worker" (expected format: key=value)`)
+
+	_, err = ParseNodeSelector("role=")
+	assert.EqualError(t, err, `invalid node selector "role=" (expected format: key=value)`)
+
+	_, err = ParseNodeSelector("role=node")
+	assert.EqualError(t, err, `invalid node role "node" (valid roles: master, worker, lb)`)
+
+	_, err = ParseNodeSelector("label.=value")
+	assert.ErrorContains(t, err, `invalid node selector key "label."`)
+
+	_, err = ParseNodeSelector("type=worker")
+	assert.ErrorContains(t, err, `invalid node selector key "type"`)
+}
@@ -0,0 +1,163 @@
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+	"github.com/MusicDin/kubitect/pkg/utils/file"
+)
+
+// Policy contains user-defined rules that are evaluated along with the
+// built-in rules of the apply action.
+type Policy struct {
+	Rules []PolicyRule `yaml:"rules"`
+}
+
+// PolicyRule is a user-defined rule. Its path uses the same syntax as the
+// paths of built-in rules.
+type PolicyRule struct {
+	// Path of the change.
+	Path string `yaml:"path"`
+
+	// ChangeType is a type of the change (create, modify, delete). If
+	// omitted, any change type is matched.
+	ChangeType string `yaml:"changeType,omitempty"`
+
+	// Type is a type of the rule (warn, error).
+	Type string `yaml:"type"`
+
+	// Message is shown when the rule is matched.
+	Message string `yaml:"message,omitempty"`
+
+	// Actions limit the rule to the given apply actions. If omitted, the
+	// rule applies to all actions.
+	Actions []ApplyAction `yaml:"actions,omitempty"`
+
+	// Conditions limit the rule to changes whose values satisfy all
+	// conditions.
+	Conditions []PolicyCondition `yaml:"conditions,omitempty"`
+}
+
+// PolicyCondition is a predicate over values of a change.
+type PolicyCondition struct {
+	// Value is the compared value of the change (before, after). If
+	// omitted, the value after the change is compared.
+	Value string `yaml:"value,omitempty"`
+
+	// Operator determines how the values are compared.
+	Operator string `yaml:"operator"`
+
+	// Operand is a literal the value is compared with.
+	Operand string `yaml:"operand,omitempty"`
+
+	// CompareWith compares the value with the other value of the same
+	// change (before, after) instead of the operand.
+	CompareWith string `yaml:"compareWith,omitempty"`
+
+	// Not negates the condition.
+	Not bool `yaml:"not,omitempty"`
+}
+
+// Condition converts the policy condition into an event condition.
+func (c PolicyCondition) Condition() event.Condition {
+	return event.Condition{
+		Value:       event.ConditionValue(strings.ToLower(c.Value)),
+		Operator:    event.ConditionOperator(c.Operator),
+		Operand:     c.Operand,
+		CompareWith: event.ConditionValue(strings.ToLower(c.CompareWith)),
+		Not:         c.Not,
+	}
+}
+
+// Rule converts the policy rule into a validated event rule.
+func (r PolicyRule) Rule() (event.Rule, error) {
+	rule := event.Rule{
+		MatchPath:             event.NewRulePath(r.Path),
+		MatchChangeType:       cmp.ChangeType(strings.ToLower(r.ChangeType)),
+		MatchAnchorChangeType: true,
+		Message:               r.Message,
+		Policy:                true,
+	}
+
+	for _, c := range r.Conditions {
+		rule.MatchConditions = append(rule.MatchConditions, c.Condition())
+	}
+
+	if rule.MatchChangeType == "any" {
+		rule.MatchChangeType = cmp.Any
+	}
+
+	switch strings.ToLower(r.Type) {
+	case "warn":
+		rule.Type = event.Warn
+	case "error":
+		rule.Type = event.Error
+	default:
+		return rule, fmt.Errorf("invalid rule type %q (valid types: warn, error)", r.Type)
+	}
+
+	for _, a := range r.Actions {
+		if _, err := ToApplyActionType(a.String()); err != nil || a == AUTO {
+			return rule, fmt.Errorf("invalid action %q (valid actions: %s, %s, %s, %s)", a, CREATE, UPGRADE, SCALE, SCALE_UPGRADE)
+		}
+	}
+
+	if rule.Message == "" {
+		rule.Message = fmt.Sprintf("Change is not allowed by the policy rule %q.", r.Path)
+	}
+
+	return rule, rule.Validate()
+}
+
+// readPolicy reads the policy file on the given path.
+func readPolicy(path string) (*Policy, error) {
+	if !file.Exists(path) {
+		return nil, fmt.Errorf("policy file %q does not exist", path)
+	}
+
+	p, err := file.ReadYamlStrict(path, Policy{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %q: %v", path, err)
+	}
+
+	return p, nil
+}
+
+// policyRules returns policy rules that apply to the given action. Rules
+// are read from the policy file referenced in the new configuration and
+// from the policy file provided by the user.
+func (c *Cluster) policyRules(action ApplyAction) ([]event.Rule, error) {
+	var paths []string
+
+	if c.NewConfig.PolicyFile != "" {
+		paths = append(paths, string(c.NewConfig.PolicyFile))
+	}
+
+	if c.PolicyPath != "" {
+		paths = append(paths, c.PolicyPath)
+	}
+
+	var rules []event.Rule
+	for _, path := range paths {
+		p, err := readPolicy(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, r := range p.Rules {
+			rule, err := r.Rule()
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy file %q: rule %d: %v", path, i+1, err)
+			}
+
+			if len(r.Actions) == 0 || slices.Contains(r.Actions, action) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,273 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/defaults"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePolicy writes the given policy into a temporary file and returns
+// its path.
+func writePolicy(t *testing.T, policy string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(policy), 0644))
+
+	return path
+}
+
+func TestPolicyRule(t *testing.T) {
+	r := PolicyRule{
+		Path:       "cluster.nodes.worker.instances.@",
+		ChangeType: "delete",
+		Type:       "error",
+	}
+
+	rule, err := r.Rule()
+	require.NoError(t, err)
+	assert.Equal(t, event.Error, rule.Type)
+	assert.True(t, rule.Policy)
+	assert.Equal(t, `Change is not allowed by the policy rule "cluster.nodes.worker.instances.@".`, rule.Message)
+}
+
+func TestPolicyRule_Invalid(t *testing.T) {
+	_, err := PolicyRule{Path: "a", Type: "allow"}.Rule()
+	assert.EqualError(t, err, `invalid rule type "allow" (valid types: warn, error)`)
+
+	_, err = PolicyRule{Path: "a.@.@", Type: "warn"}.Rule()
+	assert.ErrorContains(t, err, "Only one anchor")
+
+	_, err = PolicyRule{Path: "a", Type: "warn", ChangeType: "update"}.Rule()
+	assert.ErrorContains(t, err, "Invalid change type")
+
+	_, err = PolicyRule{Path: "a", Type: "warn", Actions: []ApplyAction{"auto"}}.Rule()
+	assert.ErrorContains(t, err, `invalid action "auto"`)
+}
+
+func TestPolicyRule_Conditions(t *testing.T) {
+	r := PolicyRule{
+		Path: "kubernetes.version",
+		Type: "error",
+		Conditions: []PolicyCondition{
+			{Operator: "sameMinor", CompareWith: "Before", Not: true},
+		},
+	}
+
+	rule, err := r.Rule()
+	require.NoError(t, err)
+	assert.Equal(t, []event.Condition{{Operator: event.SameMinor, CompareWith: event.ValueBefore, Not: true}}, rule.MatchConditions)
+
+	r.Conditions = []PolicyCondition{{Operator: "gt", Operand: "many"}}
+
+	_, err = r.Rule()
+	assert.ErrorContains(t, err, `Invalid operand "many" for operator "gt"`)
+}
+
+func TestEvents_Policy(t *testing.T) {
+	c := MockCluster(t)
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].IP = "192.168.113.10"
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].IP = "192.168.113.11"
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM++
+
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: cluster.nodes.*.instances.*.ram
+    changeType: modify
+    type: error
+    message: Node resources are managed by the platform team.
+  - path: cluster.nodes.*.instances.*.ip
+    type: warn
+`)
+
+	events, err := c.events(CREATE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 2)
+
+	// Policy rule cannot relax a built-in rule.
+	assert.Contains(t, errs[0].Rule.Message, "Changing IP or MAC address")
+	assert.False(t, errs[0].Rule.Policy)
+
+	assert.Equal(t, "Node resources are managed by the platform team.", errs[1].Rule.Message)
+	assert.True(t, errs[1].Rule.Policy)
+}
+
+func TestEvents_PolicyActions(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM++
+	c.NewConfig.PolicyFile = config.File(writePolicy(t, `
+rules:
+  - path: cluster.nodes.master.instances.*.ram
+    type: error
+    actions: [scale]
+`))
+
+	events, err := c.events(CREATE)
+	require.NoError(t, err)
+	assert.Empty(t, events.FilterByRuleType(event.Error))
+
+	c.NewConfig.PolicyFile = config.File(writePolicy(t, `
+rules:
+  - path: cluster.nodes.master.instances.*.ram
+    type: error
+    actions: [create]
+`))
+
+	events, err = c.events(CREATE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].Rule.Policy)
+}
+
+// Test expects a rule anchored at the node to match only the removal of
+// the node, and not the removal of its label.
+func TestEvents_PolicyCriticalWorker(t *testing.T) {
+	c := MockCluster(t)
+
+	critical := config.Labels{"critical": "true"}
+	c.NewConfig.Cluster.Nodes.Worker.Instances = []config.WorkerInstance{
+		{Id: "1", Labels: critical},
+		{Id: "2", Labels: critical},
+	}
+
+	require.NoError(t, defaults.Set(c.NewConfig))
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: cluster.nodes.worker.instances.@.labels.critical
+    changeType: delete
+    type: error
+    conditions:
+      - value: before
+        operator: eq
+        operand: "true"
+`)
+
+	// Remove the label.
+	workers := &c.NewConfig.Cluster.Nodes.Worker
+	workers.Instances[0].Labels = nil
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+	assert.Empty(t, events.Filter(func(e event.Event) bool { return e.Rule.Policy }))
+
+	// Remove the node.
+	workers.Instances = workers.Instances[1:]
+
+	events, err = c.events(SCALE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].Rule.Policy)
+	assert.Equal(t, "cluster.nodes.worker.instances.1", errs[0].Change.Path)
+}
+
+func TestEvents_PolicyInvalid(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM++
+
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: a
+    type: ignore
+`)
+
+	_, err := c.events(CREATE)
+	assert.ErrorContains(t, err, "rule 1: invalid rule type")
+
+	c.PolicyPath = writePolicy(t, "rules:\n  - unknown: a\n")
+
+	_, err = c.events(CREATE)
+	assert.ErrorContains(t, err, "invalid policy file")
+
+	c.PolicyPath = "missing.yaml"
+
+	_, err = c.events(CREATE)
+	assert.EqualError(t, err, `policy file "missing.yaml" does not exist`)
+}
+
+func TestEvents_PolicyConditions(t *testing.T) {
+	c := MockCluster(t)
+	c.NewConfig.Cluster.Nodes.Worker.Instances = []config.WorkerInstance{
+		{Id: "1", Labels: config.Labels{"critical": "true"}},
+		{Id: "2", Labels: config.Labels{"critical": "false"}},
+	}
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = nil
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: cluster.nodes.worker.instances.@.labels.critical
+    changeType: delete
+    type: error
+    message: Critical workers cannot be removed.
+    conditions:
+      - value: before
+        operator: eq
+        operand: "true"
+`)
+
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Critical workers cannot be removed.", errs[0].Rule.Message)
+	assert.Equal(t, "cluster.nodes.worker.instances.1", errs[0].Change.Path)
+}
+
+func TestEvents_PolicyConditions_Shrink(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.PolicyPath = writePolicy(t, `
+rules:
+  - path: cluster.nodes.*.instances.*.ram
+    changeType: modify
+    type: error
+    message: RAM of a node cannot be decreased.
+    conditions:
+      - operator: lt
+        compareWith: before
+`)
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM++
+
+	events, err := c.events(CREATE)
+	require.NoError(t, err)
+	assert.Empty(t, events.FilterByRuleType(event.Error))
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM -= 2
+
+	events, err = c.events(CREATE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "RAM of a node cannot be decreased.", errs[0].Rule.Message)
+}
@@ -1,10 +1,21 @@
 package provisioner
 
-import "github.com/MusicDin/kubitect/pkg/cluster/event"
+import (
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+)
 
 type Provisioner interface {
 	Init(events []event.Event) error
 	Plan() (bool, error)
 	Apply() error
 	Destroy() error
+
+	// Resize changes resources (cpu, ram and main disk size) of the
+	// given node in place.
+	Resize(node config.Instance) error
+
+	// Replace destroys and recreates the virtual machine of the given
+	// node.
+	Replace(node config.Instance) error
 }
@@ -4,14 +4,17 @@ import (
 	"testing"
 
 	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
 )
 
 type provisionerMock struct{}
 
-func (m provisionerMock) Init([]event.Event) error { return nil }
-func (m provisionerMock) Plan() (bool, error)      { return true, nil }
-func (m provisionerMock) Apply() error             { return nil }
-func (m provisionerMock) Destroy() error           { return nil }
+func (m provisionerMock) Init([]event.Event) error      { return nil }
+func (m provisionerMock) Plan() (bool, error)           { return true, nil }
+func (m provisionerMock) Apply() error                  { return nil }
+func (m provisionerMock) Destroy() error                { return nil }
+func (m provisionerMock) Resize(config.Instance) error  { return nil }
+func (m provisionerMock) Replace(config.Instance) error { return nil }
 
 func MockProvisioner(t *testing.T) Provisioner {
 	return provisionerMock{}
@@ -0,0 +1,71 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
+
+// Replace destroys and recreates the virtual machine of the given node,
+// along with its main disk and cloud-init disk. Data disks are preserved.
+//
+// The virtual machine is recreated from the current configuration,
+// therefore the node's IP and MAC addresses must be set in the
+// configuration to be preserved.
+func (t *terraform) Replace(node config.Instance) error {
+	host, err := t.nodeHost(node)
+	if err != nil {
+		return err
+	}
+
+	if err := t.init(); err != nil {
+		return err
+	}
+
+	domain := fmt.Sprintf("%s-%s-%s", t.cfg.Cluster.Name, node.GetTypeName(), node.GetID())
+
+	ui.Printf(ui.INFO, "Replacing virtual machine %q...\n", domain)
+
+	args := []string{
+		flag("auto-approve"),
+		flag("input", false),
+		flag("lock", true),
+		flag("lock-timeout", "0s"),
+		flag("parallelism", 10),
+		flag("refresh", true),
+	}
+
+	// Apply is limited to the node's module, so that changes of other
+	// resources are not applied while the node is replaced.
+	args = append(args, flag("target", nodeModule(host.Name, node)))
+
+	for _, addr := range replaceAddresses(host.Name, node) {
+		args = append(args, flag("replace", addr))
+	}
+
+	_, err = t.runCmd("apply", args, true)
+	if err != nil {
+		return fmt.Errorf("replace virtual machine %q: %v", domain, err)
+	}
+
+	return nil
+}
+
+// nodeModule returns the address of the Terraform module that contains
+// resources of the given node.
+func nodeModule(hostName string, node config.Instance) string {
+	return fmt.Sprintf("module.host_%s.module.%s_module[%q]", hostName, node.GetTypeName(), node.GetID())
+}
+
+// replaceAddresses returns addresses of Terraform resources that need to
+// be replaced to recreate the virtual machine of the given node.
+func replaceAddresses(hostName string, node config.Instance) []string {
+	module := nodeModule(hostName, node)
+
+	return []string{
+		module + ".libvirt_cloudinit_disk.cloud_init",
+		module + ".libvirt_volume.vm_main_disk",
+		module + ".libvirt_domain.vm_domain",
+	}
+}
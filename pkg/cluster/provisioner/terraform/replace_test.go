@@ -0,0 +1,27 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeModule(t *testing.T) {
+	assert.Equal(t, `module.host_localhost.module.worker_module["3"]`, nodeModule("localhost", config.WorkerInstance{Id: "3"}))
+	assert.Equal(t, `module.host_remote.module.lb_module["1"]`, nodeModule("remote", config.LBInstance{Id: "1"}))
+}
+
+func TestReplaceAddresses(t *testing.T) {
+	node := config.WorkerInstance{Id: "3"}
+
+	assert.Equal(t, []string{
+		`module.host_localhost.module.worker_module["3"].libvirt_cloudinit_disk.cloud_init`,
+		`module.host_localhost.module.worker_module["3"].libvirt_volume.vm_main_disk`,
+		`module.host_localhost.module.worker_module["3"].libvirt_domain.vm_domain`,
+	}, replaceAddresses("localhost", node))
+
+	lb := config.LBInstance{Id: "1"}
+	assert.Contains(t, replaceAddresses("remote", lb), `module.host_remote.module.lb_module["1"].libvirt_domain.vm_domain`)
+}
@@ -0,0 +1,139 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+)
+
+var (
+	// virsh runs virsh command against the libvirt daemon on the given
+	// URI and returns its output. Replaced in tests.
+	virsh = runVirsh
+
+	// Maximum time to wait for the virtual machine to shut down, and
+	// the interval in which its state is checked.
+	shutdownTimeout  = 5 * time.Minute
+	shutdownInterval = 2 * time.Second
+)
+
+// Resize changes the number of virtual CPUs, the amount of RAM and the
+// size of the main disk of the node's virtual machine to the values of
+// the given node. The virtual machine is shut down while its resources
+// are changed and started afterwards. On boot, cloud-init grows the root
+// partition to the size of the main disk.
+//
+// Resources are changed in place, therefore Terraform ignores them for
+// existing virtual machines. The main disk can only be grown.
+func (t *terraform) Resize(node config.Instance) error {
+	host, err := t.nodeHost(node)
+	if err != nil {
+		return err
+	}
+
+	uri, err := hostUri(host)
+	if err != nil {
+		return err
+	}
+
+	clusterName := t.cfg.Cluster.Name
+	domain := fmt.Sprintf("%s-%s-%s", clusterName, node.GetTypeName(), node.GetID())
+	pool := fmt.Sprintf("%s-main-resource-pool", clusterName)
+
+	ui.Printf(ui.INFO, "Shutting down virtual machine %q...\n", domain)
+
+	if err := shutdownDomain(uri, domain); err != nil {
+		return err
+	}
+
+	cpu := fmt.Sprint(node.GetCPU())
+	ram := fmt.Sprintf("%dG", node.GetRAM())
+	disk := fmt.Sprintf("%dG", node.GetMainDiskSize())
+
+	cmds := [][]string{
+		// Maximum must be raised before the current value.
+		{"setvcpus", domain, cpu, "--config", "--maximum"},
+		{"setvcpus", domain, cpu, "--config"},
+		{"setmaxmem", domain, ram, "--config"},
+		{"setmem", domain, ram, "--config"},
+		{"vol-resize", domain + "-main-disk", disk, "--pool", pool},
+		{"start", domain},
+	}
+
+	ui.Printf(ui.INFO, "Resizing virtual machine %q (cpu: %s, ram: %s, mainDiskSize: %s)...\n", domain, cpu, ram, disk)
+
+	for _, cmd := range cmds {
+		if _, err := virsh(uri, cmd...); err != nil {
+			return fmt.Errorf("resize virtual machine %q: %v", domain, err)
+		}
+	}
+
+	return nil
+}
+
+// nodeHost returns the host on which the given node is deployed.
+func (t *terraform) nodeHost(node config.Instance) (config.Host, error) {
+	if node.GetHost() == "" {
+		return defaultHost(t.cfg.Hosts)
+	}
+
+	for _, h := range t.cfg.Hosts {
+		if h.Name == node.GetHost() {
+			return h, nil
+		}
+	}
+
+	return config.Host{}, fmt.Errorf("host %q of node %q not found", node.GetHost(), node.GetID())
+}
+
+// shutdownDomain gracefully shuts down the given domain and waits until
+// it is shut off.
+func shutdownDomain(uri string, domain string) error {
+	deadline := time.Now().Add(shutdownTimeout)
+
+	for i := 0; ; i++ {
+		state, err := virsh(uri, "domstate", domain)
+		if err != nil {
+			return fmt.Errorf("get state of virtual machine %q: %v", domain, err)
+		}
+
+		if state == "shut off" {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("virtual machine %q has not shut down within %s", domain, shutdownTimeout)
+		}
+
+		if i == 0 {
+			_, err := virsh(uri, "shutdown", domain)
+			if err != nil {
+				return fmt.Errorf("shut down virtual machine %q: %v", domain, err)
+			}
+		}
+
+		time.Sleep(shutdownInterval)
+	}
+}
+
+// runVirsh runs virsh command against the libvirt daemon on the given URI
+// and returns its trimmed output.
+func runVirsh(uri string, args ...string) (string, error) {
+	args = append([]string{"--connect", uri}, args...)
+
+	cmd := exec.Command("virsh", args...)
+	cmd.Env = []string{fmt.Sprintf("PATH=%s", os.Getenv("PATH"))}
+	cmd.Stderr = ui.Streams().Err().File()
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("virsh %s: %v", strings.Join(args[2:], " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,89 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockVirsh replaces virsh with a function that records executed commands.
+// Domain is reported as running until it is shut down.
+func mockVirsh(t *testing.T) *[]string {
+	var calls []string
+	state := "running"
+
+	tmp, tmpInterval := virsh, shutdownInterval
+	t.Cleanup(func() { virsh, shutdownInterval = tmp, tmpInterval })
+
+	virsh = func(uri string, args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+
+		switch args[0] {
+		case "domstate":
+			return state, nil
+		case "shutdown":
+			state = "shut off"
+		}
+
+		return "", nil
+	}
+
+	shutdownInterval = 0
+	return &calls
+}
+
+func TestResize(t *testing.T) {
+	ui.MockGlobalUi(t, ui.UiOptions{NoColor: true})
+	calls := mockVirsh(t)
+
+	tf := &terraform{
+		cfg: &config.Config{
+			Hosts: []config.Host{config.MockLocalHost(t, "localhost", true)},
+			Cluster: config.Cluster{
+				Name: "test",
+			},
+		},
+	}
+
+	node := config.WorkerInstance{
+		Id:           "1",
+		CPU:          4,
+		RAM:          8,
+		MainDiskSize: 64,
+	}
+
+	require.NoError(t, tf.Resize(node))
+
+	expect := []string{
+		"domstate test-worker-1",
+		"shutdown test-worker-1",
+		"domstate test-worker-1",
+		"setvcpus test-worker-1 4 --config --maximum",
+		"setvcpus test-worker-1 4 --config",
+		"setmaxmem test-worker-1 8G --config",
+		"setmem test-worker-1 8G --config",
+		"vol-resize test-worker-1-main-disk 64G --pool test-main-resource-pool",
+		"start test-worker-1",
+	}
+
+	assert.Equal(t, expect, *calls)
+}
+
+func TestResize_UnknownHost(t *testing.T) {
+	ui.MockGlobalUi(t, ui.UiOptions{NoColor: true})
+	mockVirsh(t)
+
+	tf := &terraform{
+		cfg: &config.Config{
+			Hosts: []config.Host{config.MockLocalHost(t, "localhost", true)},
+		},
+	}
+
+	err := tf.Resize(config.WorkerInstance{Id: "1", Host: "missing"})
+	assert.EqualError(t, err, `host "missing" of node "1" not found`)
+}
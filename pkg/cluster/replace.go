@@ -0,0 +1,274 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// kubectl runs kubectl command locally against the cluster using the
+// cluster's kubeconfig and returns its standard output. Note that if
+// kubectl is not present locally, the command will fail.
+var kubectl = func(c *Cluster, args ...string) ([]byte, error) {
+	if !c.ContainsKubeconfig() {
+		return nil, fmt.Errorf("cluster %q does not have a Kubeconfig file", c.Name)
+	}
+
+	kc := exec.NewLocalClient()
+	kc.SetEnv("KUBECONFIG", c.KubeconfigPath())
+
+	out, err := kc.Output("kubectl", args...)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl %s: %v", strings.Join(args, " "), err)
+	}
+
+	return out, nil
+}
+
+// ReplaceNode replaces the node with the given name with a new one. The
+// node is drained and removed from the Kubernetes cluster, after which its
+// virtual machine is destroyed and recreated with the same ID, IP and MAC
+// address. The new node is then joined to the cluster and labels and
+// taints of the replaced node are restored.
+//
+// The applied configuration is left unchanged, since the cluster ends up
+// in the same state.
+func (c *Cluster) ReplaceNode(name string, o interfaces.DrainOptions) error {
+	n, err := c.FindNode(name)
+	if err != nil {
+		return err
+	}
+
+	name = c.nodeName(n)
+
+	// Replacement works with a copy of the applied configuration, in
+	// which the node's addresses are set to the provisioned ones.
+	cfg := withNodeAddresses(*c.AppliedConfig, n)
+	c.NewConfig = &cfg
+
+	cn := c.configNode(n)
+	if cn == nil {
+		return fmt.Errorf("node %q is not part of the applied configuration", name)
+	}
+
+	if n.GetTypeName() == "master" && len(cfg.Cluster.Nodes.Master.Instances) == 1 {
+		return fmt.Errorf("node %q cannot be replaced, because it is the only control plane node", name)
+	}
+
+	err = ui.Ask(fmt.Sprintf("Node %q will be destroyed and recreated. Proceed?", name))
+	if err != nil {
+		return err
+	}
+
+	unlock, err := c.Lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	var kn *kubeNode
+	if n.GetTypeName() != "lb" {
+		kn, err = c.kubeNode(name)
+		if err != nil {
+			ui.Printf(ui.WARN, "Labels and taints of node %q will not be restored: %v\n", name, err)
+		}
+	}
+
+	ui.Printf(ui.INFO, "Removing node %q from the cluster...\n", name)
+
+	if err := c.Manager().DrainNode(n, o); err != nil {
+		return err
+	}
+
+	if err := c.removeNode(cn); err != nil {
+		return err
+	}
+
+	if err := c.Provisioner().Init(nil); err != nil {
+		return err
+	}
+
+	if err := c.Provisioner().Replace(cn); err != nil {
+		return err
+	}
+
+	if err := c.Sync(); err != nil {
+		return err
+	}
+
+	ui.Printf(ui.INFO, "Joining node %q to the cluster...\n", name)
+
+	if err := c.Manager().Sync(); err != nil {
+		return err
+	}
+
+	if err := c.Manager().ScaleUp(event.Events{nodeEvent(event.Action_ScaleUp, cn)}); err != nil {
+		return err
+	}
+
+	if err := c.Manager().UncordonNode(n); err != nil {
+		return err
+	}
+
+	if kn != nil {
+		return c.restoreNodeMetadata(name, *kn)
+	}
+
+	return nil
+}
+
+// removeNode removes the node from the Kubernetes cluster using the
+// manager's scale down logic. While the node is being removed, the
+// configuration temporarily excludes it, so that the manager does not
+// use it to manage the cluster.
+func (c *Cluster) removeNode(n config.Instance) error {
+	newCfg := *c.NewConfig
+	defer func() { *c.NewConfig = newCfg }()
+
+	*c.NewConfig = withoutNewNodes(newCfg, event.Events{nodeEvent(event.Action_ScaleUp, n)})
+
+	if err := c.Manager().Init(); err != nil {
+		return err
+	}
+
+	if n.GetTypeName() == "lb" {
+		// Load balancers are not part of the Kubernetes cluster.
+		return nil
+	}
+
+	return c.Manager().ScaleDown(event.Events{nodeEvent(event.Action_ScaleDown, n)})
+}
+
+// kubeNode returns the Kubernetes node with the given name.
+func (c *Cluster) kubeNode(name string) (*kubeNode, error) {
+	out, err := kubectl(c, "get", "node", name, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var n kubeNode
+	if err := json.Unmarshal(out, &n); err != nil {
+		return nil, fmt.Errorf("parse Kubernetes node: %v", err)
+	}
+
+	return &n, nil
+}
+
+// restoreNodeMetadata restores labels and taints of the given Kubernetes
+// node on the node with the given name. Labels and taints managed by
+// Kubernetes itself are skipped.
+func (c *Cluster) restoreNodeMetadata(name string, kn kubeNode) error {
+	var labels []string
+	for k, v := range kn.Metadata.Labels {
+		if !isSystemKey(k) {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+
+	var taints []string
+	for _, t := range kn.Spec.Taints {
+		if !isSystemKey(t.Key) {
+			taints = append(taints, t.String())
+		}
+	}
+
+	slices.Sort(labels)
+
+	ui.Printf(ui.INFO, "Restoring labels and taints of node %q...\n", name)
+
+	if len(labels) > 0 {
+		args := append([]string{"label", "node", name, "--overwrite"}, labels...)
+		if _, err := kubectl(c, args...); err != nil {
+			return fmt.Errorf("restore labels of node %q: %v", name, err)
+		}
+	}
+
+	if len(taints) > 0 {
+		args := append([]string{"taint", "node", name, "--overwrite"}, taints...)
+		if _, err := kubectl(c, args...); err != nil {
+			return fmt.Errorf("restore taints of node %q: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isSystemKey returns true if the label or taint key belongs to the
+// Kubernetes or K3s domain. Such labels and taints are set by Kubernetes
+// components when the node joins the cluster.
+func isSystemKey(key string) bool {
+	domain, _, ok := strings.Cut(key, "/")
+	if !ok {
+		return false
+	}
+
+	for _, d := range []string{"kubernetes.io", "k8s.io", "k3s.io", "cattle.io"} {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeEvent returns a scale event that either removes or adds the given
+// node, which allows reusing the scaling logic of the manager.
+func nodeEvent(action event.ActionType, n config.Instance) event.Event {
+	e := event.Event{
+		Rule: event.Rule{ActionType: action},
+	}
+
+	if action == event.Action_ScaleDown {
+		e.Change = cmp.Change{Type: cmp.Delete, ValueBefore: n}
+	} else {
+		e.Change = cmp.Change{Type: cmp.Create, ValueAfter: n}
+	}
+
+	return e
+}
+
+// withNodeAddresses returns a copy of the given configuration in which
+// the IP and MAC addresses of the given node are set to the addresses of
+// the provisioned node.
+func withNodeAddresses(cfg config.Config, n config.Instance) config.Config {
+	nodes := &cfg.Cluster.Nodes
+	nodes.Master.Instances = slices.Clone(nodes.Master.Instances)
+	nodes.Worker.Instances = slices.Clone(nodes.Worker.Instances)
+	nodes.LoadBalancer.Instances = slices.Clone(nodes.LoadBalancer.Instances)
+
+	matches := func(i config.Instance) bool {
+		return i.GetTypeName() == n.GetTypeName() && i.GetID() == n.GetID()
+	}
+
+	for i, m := range nodes.Master.Instances {
+		if matches(m) {
+			nodes.Master.Instances[i].IP = n.GetIP()
+			nodes.Master.Instances[i].MAC = n.GetMAC()
+		}
+	}
+
+	for i, w := range nodes.Worker.Instances {
+		if matches(w) {
+			nodes.Worker.Instances[i].IP = n.GetIP()
+			nodes.Worker.Instances[i].MAC = n.GetMAC()
+		}
+	}
+
+	for i, l := range nodes.LoadBalancer.Instances {
+		if matches(l) {
+			nodes.LoadBalancer.Instances[i].IP = n.GetIP()
+			nodes.LoadBalancer.Instances[i].MAC = n.GetMAC()
+		}
+	}
+
+	return cfg
+}
@@ -0,0 +1,234 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/models/infra"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replaceManager additionally records initialization and scaling of the
+// cluster.
+type replaceManager struct {
+	resizeManager
+}
+
+func (m replaceManager) Init() error {
+	m.calls = append(m.calls, "init")
+	return nil
+}
+
+func (m replaceManager) Sync() error {
+	m.calls = append(m.calls, "sync")
+	return nil
+}
+
+func (m replaceManager) ScaleDown(events event.Events) error {
+	for _, e := range events {
+		m.record("scale-down", e.Change.ValueBefore.(config.Instance))
+	}
+
+	return nil
+}
+
+func (m replaceManager) ScaleUp(events event.Events) error {
+	for _, e := range events {
+		m.record("scale-up", e.Change.ValueAfter.(config.Instance))
+	}
+
+	return nil
+}
+
+type replaceProvisioner struct {
+	resizeProvisioner
+	replaced *config.Instance
+}
+
+func (p replaceProvisioner) Replace(n config.Instance) error {
+	*p.replaced = n
+	return p.record("replace", n)
+}
+
+// mockReplaceCluster returns a cluster with two masters and a worker in
+// both applied and provisioned configuration.
+func mockReplaceCluster(t *testing.T) (*ClusterMock, *resizeRecorder, *config.Instance) {
+	t.Helper()
+
+	c := MockCluster(t)
+	mockControlPlane(t, c, 2)
+
+	c.NewConfig.Cluster.Nodes.Worker.Instances = []config.WorkerInstance{{Id: "1"}}
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.InfraConfig = &infra.Config{
+		Nodes: config.Nodes{
+			Master: config.Master{
+				Instances: []config.MasterInstance{
+					{Id: "1", IP: "10.10.0.10", MAC: "52:54:00:00:00:10"},
+					{Id: "2", IP: "10.10.0.11", MAC: "52:54:00:00:00:11"},
+				},
+			},
+			Worker: config.Worker{
+				Instances: []config.WorkerInstance{
+					{Id: "1", IP: "10.10.0.20", MAC: "52:54:00:00:00:20"},
+				},
+			},
+		},
+	}
+
+	r := &resizeRecorder{}
+	var replaced config.Instance
+
+	c.exec = replaceManager{resizeManager{c.exec, r}}
+	c.prov = replaceProvisioner{resizeProvisioner{c.prov, r}, &replaced}
+
+	return c, r, &replaced
+}
+
+// mockKubectl replaces the kubectl command for the duration of the test.
+// Getting a node returns the given node JSON, while other commands are
+// recorded.
+func mockKubectl(t *testing.T, node string) *[]string {
+	t.Helper()
+
+	tmp := kubectl
+
+	var commands []string
+	kubectl = func(c *Cluster, args ...string) ([]byte, error) {
+		if args[0] == "get" {
+			return []byte(node), nil
+		}
+
+		commands = append(commands, strings.Join(args, " "))
+		return nil, nil
+	}
+
+	t.Cleanup(func() { kubectl = tmp })
+
+	return &commands
+}
+
+func TestReplaceNode(t *testing.T) {
+	c, r, replaced := mockReplaceCluster(t)
+	name := c.Name + "-worker-1"
+
+	commands := mockKubectl(t, `{
+		"metadata": {
+			"name": "cluster-mock-worker-1",
+			"labels": {
+				"kubernetes.io/hostname": "cluster-mock-worker-1",
+				"node-role.kubernetes.io/worker": "",
+				"disk": "ssd",
+				"example.com/zone": "a"
+			}
+		},
+		"spec": {
+			"taints": [
+				{"key": "node.kubernetes.io/unschedulable", "effect": "NoSchedule"},
+				{"key": "dedicated", "value": "db", "effect": "NoExecute"}
+			]
+		}
+	}`)
+
+	applied := *c.AppliedConfig
+
+	require.NoError(t, c.ReplaceNode("worker-1", interfaces.DrainOptions{}))
+
+	expect := []string{
+		"drain worker-1",
+		"init",
+		"scale-down worker-1",
+		"replace worker-1",
+		"sync",
+		"scale-up worker-1",
+		"uncordon worker-1",
+	}
+
+	assert.Equal(t, expect, r.calls)
+	assert.Equal(t, []string{
+		fmt.Sprintf("label node %s --overwrite disk=ssd example.com/zone=a", name),
+		fmt.Sprintf("taint node %s --overwrite dedicated=db:NoExecute", name),
+	}, *commands)
+
+	// Replaced node keeps its addresses.
+	require.NotNil(t, *replaced)
+	assert.Equal(t, "10.10.0.20", string((*replaced).GetIP()))
+	assert.Equal(t, "52:54:00:00:00:20", string((*replaced).GetMAC()))
+
+	// Applied configuration is not modified.
+	assert.Equal(t, applied, *c.AppliedConfig)
+	assert.Empty(t, c.AppliedConfig.Cluster.Nodes.Worker.Instances[0].IP)
+}
+
+func TestReplaceNode_WithoutKubernetesNode(t *testing.T) {
+	c, r, _ := mockReplaceCluster(t)
+
+	tmp := kubectl
+	kubectl = func(c *Cluster, args ...string) ([]byte, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	defer func() { kubectl = tmp }()
+
+	require.NoError(t, c.ReplaceNode("master-2", interfaces.DrainOptions{}))
+	assert.Contains(t, r.calls, "replace master-2")
+	assert.Contains(t, c.Ui().ReadStderr(t), "will not be restored")
+}
+
+func TestReplaceNode_DefaultDataDisks(t *testing.T) {
+	c, _, replaced := mockReplaceCluster(t)
+	mockDefaultDataDisks(t, c)
+
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	mockKubectl(t, `{"metadata": {"name": "cluster-mock-master-2"}}`)
+
+	require.NoError(t, c.ReplaceNode("master-2", interfaces.DrainOptions{}))
+
+	// Node is recreated with the same data disks.
+	disks := []config.DataDisk{{Name: "data", Size: 10}}
+	assert.Equal(t, disks, (*replaced).(config.MasterInstance).DataDisks)
+
+	for _, n := range c.NewConfig.Cluster.Nodes.Master.Instances {
+		assert.Equal(t, disks, n.DataDisks)
+	}
+
+	for _, n := range c.AppliedConfig.Cluster.Nodes.Master.Instances {
+		assert.Equal(t, disks, n.DataDisks)
+	}
+}
+
+func TestReplaceNode_OnlyMaster(t *testing.T) {
+	c, _, _ := mockReplaceCluster(t)
+	c.AppliedConfig.Cluster.Nodes.Master.Instances = c.AppliedConfig.Cluster.Nodes.Master.Instances[:1]
+
+	err := c.ReplaceNode("master-1", interfaces.DrainOptions{})
+	assert.EqualError(t, err, fmt.Sprintf("node %q cannot be replaced, because it is the only control plane node", c.Name+"-master-1"))
+}
+
+func TestReplaceNode_NotApplied(t *testing.T) {
+	c, _, _ := mockReplaceCluster(t)
+	c.AppliedConfig.Cluster.Nodes.Worker.Instances = nil
+
+	err := c.ReplaceNode("worker-1", interfaces.DrainOptions{})
+	assert.EqualError(t, err, fmt.Sprintf("node %q is not part of the applied configuration", c.Name+"-worker-1"))
+}
+
+func TestIsSystemKey(t *testing.T) {
+	assert.True(t, isSystemKey("kubernetes.io/hostname"))
+	assert.True(t, isSystemKey("node-role.kubernetes.io/control-plane"))
+	assert.True(t, isSystemKey("node.k8s.io/instance-type"))
+	assert.True(t, isSystemKey("node.k3s.io/hostname"))
+	assert.False(t, isSystemKey("disk"))
+	assert.False(t, isSystemKey("example.com/zone"))
+	assert.False(t, isSystemKey("notkubernetes.io/zone"))
+}
@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/ui"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+)
+
+// resizeEvents returns error events for resized nodes whose main disk
+// would shrink. Main disk can only be grown, since shrinking it would
+// corrupt the file system of the node.
+func resizeEvents(events event.Events) event.Events {
+	var rEvents event.Events
+	for _, e := range events.FilterByAction(event.Action_Resize) {
+		before, ok1 := e.Change.ValueBefore.(config.Instance)
+		after, ok2 := e.Change.ValueAfter.(config.Instance)
+
+		if !ok1 || !ok2 || after.GetMainDiskSize() >= before.GetMainDiskSize() {
+			continue
+		}
+
+		msg := fmt.Sprintf("Shrinking the main disk of a node (from %d GiB to %d GiB) is not allowed. Main disk can only be grown.", before.GetMainDiskSize(), after.GetMainDiskSize())
+
+		rEvents = append(rEvents, event.Event{
+			Rule: event.Rule{
+				Type:            event.Error,
+				MatchChangeType: cmp.Modify,
+				Message:         msg,
+			},
+			Change:             e.Change,
+			MatchedChangePaths: e.MatchedChangePaths,
+		})
+	}
+
+	return rEvents
+}
+
+// resizedNodes returns nodes whose resources have been changed. Nodes
+// are returned with the new resources.
+func resizedNodes(events event.Events) []config.Instance {
+	var nodes []config.Instance
+	for _, e := range events.FilterByAction(event.Action_Resize) {
+		if n, ok := e.Change.ValueAfter.(config.Instance); ok {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// resizeDrainOptions are used to drain nodes before they are resized.
+// Pods are evicted forcefully, since the node is restarted anyway.
+var resizeDrainOptions = interfaces.DrainOptions{
+	DeleteEmptyDirData: true,
+	Force:              true,
+}
+
+// resize resizes nodes with changed resources, one node at a time. Each
+// node is drained, resized and restarted by the provisioner, and made
+// schedulable again once it boots and becomes ready.
+func (c *Cluster) resize(events event.Events) error {
+	nodes := resizedNodes(events)
+
+	for i, n := range nodes {
+		name := c.nodeName(n)
+
+		err := c.phase("resize-"+name, func() error {
+			ui.Printf(ui.INFO, "Resizing node %q (%d/%d)...\n", name, i+1, len(nodes))
+
+			// Provisioned node is used, since its IP address is known
+			// even if it is not set in the configuration.
+			pn, err := c.FindNode(name)
+			if err != nil {
+				return err
+			}
+
+			// Ready condition of the node is not updated until the
+			// restarted node boots. Therefore, the boot ID is read
+			// before the node is resized to detect when it boots.
+			bootID, err := nodeBootID(c, string(pn.GetIP()))
+			if err != nil {
+				return fmt.Errorf("read boot ID of node %q: %v", name, err)
+			}
+
+			if err := c.Manager().DrainNode(n, resizeDrainOptions); err != nil {
+				return err
+			}
+
+			if err := c.Provisioner().Resize(n); err != nil {
+				return err
+			}
+
+			if err := c.waitNodeBoot(pn, bootID); err != nil {
+				return err
+			}
+
+			return c.Manager().UncordonNode(n)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/cluster/interfaces"
+	"github.com/MusicDin/kubitect/pkg/cluster/provisioner"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/models/infra"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resizeRecorder records node operations executed during the resize.
+type resizeRecorder struct {
+	calls []string
+}
+
+func (r *resizeRecorder) record(op string, n config.Instance) error {
+	r.calls = append(r.calls, fmt.Sprintf("%s %s-%s", op, n.GetTypeName(), n.GetID()))
+	return nil
+}
+
+type resizeManager struct {
+	interfaces.Manager
+	*resizeRecorder
+}
+
+func (m resizeManager) CordonNode(n config.Instance) error   { return m.record("cordon", n) }
+func (m resizeManager) UncordonNode(n config.Instance) error { return m.record("uncordon", n) }
+
+func (m resizeManager) DrainNode(n config.Instance, o interfaces.DrainOptions) error {
+	return m.record("drain", n)
+}
+
+type resizeProvisioner struct {
+	provisioner.Provisioner
+	*resizeRecorder
+}
+
+func (p resizeProvisioner) Resize(n config.Instance) error { return p.record("resize", n) }
+
+// rebootProvisioner additionally counts node boots, since the resized
+// node is restarted.
+type rebootProvisioner struct {
+	resizeProvisioner
+	boots *int
+}
+
+func (p rebootProvisioner) Resize(n config.Instance) error {
+	*p.boots++
+	return p.resizeProvisioner.Resize(n)
+}
+
+// mockResize records node operations and restarts resized nodes for the
+// duration of the test. Master nodes of the new configuration are used as
+// provisioned nodes. Executed node commands are returned along with the
+// recorder.
+func mockResize(t *testing.T, c *ClusterMock) (*resizeRecorder, *[]string) {
+	t.Helper()
+
+	commands, boots := mockReboot(t)
+
+	r := &resizeRecorder{}
+	c.exec = resizeManager{c.exec, r}
+	c.prov = rebootProvisioner{resizeProvisioner{c.prov, r}, boots}
+
+	c.InfraConfig = &infra.Config{}
+	for i, m := range c.NewConfig.Cluster.Nodes.Master.Instances {
+		m.IP = config.IPv4(fmt.Sprintf("10.10.0.1%d", i))
+		c.InfraConfig.Nodes.Master.Instances = append(c.InfraConfig.Nodes.Master.Instances, m)
+	}
+
+	return r, commands
+}
+
+func TestEvents_Resize(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	m := &c.NewConfig.Cluster.Nodes.Master.Instances[0]
+	m.CPU++
+	m.RAM++
+	m.MainDiskSize++
+
+	events, err := c.events(CREATE)
+	require.NoError(t, err)
+	require.Len(t, events.FilterByAction(event.Action_Resize), 1)
+	assert.Empty(t, events.FilterByRuleType(event.Error))
+
+	nodes := resizedNodes(events)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, m.CPU, nodes[0].GetCPU())
+}
+
+func TestEvents_ResizeDefault(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	// Instances inherit default values.
+	c.NewConfig.Cluster.Nodes.Master.Default.RAM++
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].RAM++
+
+	events, err := c.events(CREATE)
+	require.NoError(t, err)
+	assert.Len(t, events.FilterByAction(event.Action_Resize), 1)
+	assert.Empty(t, events.FilterByRuleType(event.Error))
+}
+
+func TestEvents_ResizeShrinkDisk(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Cluster.Nodes.Master.Instances[0].MainDiskSize--
+
+	events, err := c.events(CREATE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Rule.Message, "Main disk can only be grown.")
+}
+
+func TestApply_Resize(t *testing.T) {
+	c := MockCluster(t)
+	mockControlPlane(t, c, 3)
+
+	r, commands := mockResize(t, c)
+
+	masters := c.NewConfig.Cluster.Nodes.Master.Instances
+	masters[0].CPU++
+	masters[2].RAM++
+
+	// Skip required files check
+	tmp := env.ProjectRequiredFiles
+	env.ProjectRequiredFiles = []string{}
+	defer func() { env.ProjectRequiredFiles = tmp }()
+
+	require.NoError(t, c.Apply(CREATE.String()))
+
+	expect := []string{
+		"drain master-1",
+		"resize master-1",
+		"uncordon master-1",
+		"drain master-3",
+		"resize master-3",
+		"uncordon master-3",
+	}
+
+	assert.Equal(t, expect, r.calls)
+
+	// Resized nodes are uncordoned only after they boot.
+	assert.Equal(t, []string{
+		"10.10.0.10: cat /proc/sys/kernel/random/boot_id",
+		"10.10.0.10: cat /proc/sys/kernel/random/boot_id",
+		"10.10.0.12: cat /proc/sys/kernel/random/boot_id",
+		"10.10.0.12: cat /proc/sys/kernel/random/boot_id",
+	}, *commands)
+
+	require.NoError(t, c.Sync())
+	assert.Equal(t, masters[2].RAM, c.AppliedConfig.Cluster.Nodes.Master.Instances[2].RAM)
+}
@@ -0,0 +1,293 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/utils/exec"
+)
+
+// Possible states of the Kubernetes node Ready condition.
+const (
+	NODE_READY     = "True"
+	NODE_NOT_READY = "False"
+	NODE_UNKNOWN   = "Unknown"
+)
+
+// NodeStatus is a live status of a single cluster node. Kubernetes related
+// fields are set only for master and worker nodes, while load balancer
+// related fields are set only for load balancer nodes.
+type NodeStatus struct {
+	Name      string `json:"name" yaml:"name"`
+	Type      string `json:"type" yaml:"type"`
+	IP        string `json:"ip" yaml:"ip"`
+	Reachable bool   `json:"reachable" yaml:"reachable"`
+
+	// State of the kubelet and container runtime services (for example,
+	// active, inactive or failed).
+	Kubelet          string `json:"kubelet,omitempty" yaml:"kubelet,omitempty"`
+	ContainerRuntime string `json:"containerRuntime,omitempty" yaml:"containerRuntime,omitempty"`
+
+	// Ready condition and version of the Kubernetes node.
+	Ready   string `json:"ready,omitempty" yaml:"ready,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// State of the load balancer services.
+	HAProxy    string `json:"haproxy,omitempty" yaml:"haproxy,omitempty"`
+	Keepalived string `json:"keepalived,omitempty" yaml:"keepalived,omitempty"`
+	HoldsVIP   bool   `json:"holdsVip,omitempty" yaml:"holdsVip,omitempty"`
+
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ClusterStatus is a live status of the cluster and its nodes.
+type ClusterStatus struct {
+	Cluster   string `json:"cluster" yaml:"cluster"`
+	VIP       string `json:"vip,omitempty" yaml:"vip,omitempty"`
+	VIPHolder string `json:"vipHolder,omitempty" yaml:"vipHolder,omitempty"`
+
+	// KubernetesError is set if the status of Kubernetes nodes could not
+	// be retrieved from the cluster.
+	KubernetesError string `json:"kubernetesError,omitempty" yaml:"kubernetesError,omitempty"`
+
+	Nodes []NodeStatus `json:"nodes" yaml:"nodes"`
+}
+
+// nodeClient runs commands on a cluster node.
+type nodeClient interface {
+	SetStdin(stdin io.Reader)
+	SetStdout(stdout io.Writer)
+	SetStderr(stderr io.Writer)
+	RunCtx(ctx context.Context, command string, args ...string) error
+	OutputCtx(ctx context.Context, command string, args ...string) ([]byte, error)
+	Close() error
+}
+
+// newNodeClient returns a client that runs commands on the node with the
+// given IP address over SSH.
+var newNodeClient = func(c *Cluster, ip string) nodeClient {
+	ssh := exec.NewSSHClient(string(c.NewConfig.Cluster.NodeTemplate.User), ip).
+		WithPrivateKeyFile(c.PrivateSshKeyPath())
+
+	return &ssh
+}
+
+// kubeNodes returns the list of Kubernetes nodes in JSON format. Note that
+// if kubectl is not present locally, the command will fail.
+var kubeNodes = func(ctx context.Context, c *Cluster) ([]byte, error) {
+	if !c.ContainsKubeconfig() {
+		return nil, fmt.Errorf("cluster %q does not have a Kubeconfig file", c.Name)
+	}
+
+	kc := exec.NewLocalClient()
+	kc.SetEnv("KUBECONFIG", c.KubeconfigPath())
+
+	out, err := kc.OutputCtx(ctx, "kubectl", "get", "nodes", "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("kubectl get nodes: %v", err)
+	}
+
+	return out, nil
+}
+
+// Status checks the live status of the cluster nodes. Each node is
+// accessed over SSH, while the status of Kubernetes nodes is retrieved
+// using the cluster's kubeconfig. Nodes are checked concurrently and
+// each check must finish within the given timeout.
+func (c *Cluster) Status(timeout time.Duration) *ClusterStatus {
+	cs := &ClusterStatus{
+		Cluster: c.Name,
+		VIP:     string(c.NewConfig.Cluster.Nodes.LoadBalancer.VIP),
+	}
+
+	nodes := c.InfraConfig.Nodes.Instances()
+	cs.Nodes = make([]NodeStatus, len(nodes))
+
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n config.Instance) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			cs.Nodes[i] = c.nodeStatus(ctx, n)
+		}(i, n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	kNodes, err := readKubeNodes(ctx, c)
+	if err != nil {
+		cs.KubernetesError = err.Error()
+	}
+
+	wg.Wait()
+
+	for i, n := range cs.Nodes {
+		if n.HoldsVIP {
+			cs.VIPHolder = n.Name
+		}
+
+		if n.Type == "lb" {
+			continue
+		}
+
+		cs.Nodes[i].Ready = NODE_UNKNOWN
+
+		if kn, ok := kNodes[n.Name]; ok {
+			cs.Nodes[i].Ready = kn.ready()
+			cs.Nodes[i].Version = kn.Status.NodeInfo.KubeletVersion
+		}
+	}
+
+	return cs
+}
+
+// nodeStatus checks the status of services running on the given node.
+func (c *Cluster) nodeStatus(ctx context.Context, n config.Instance) NodeStatus {
+	s := NodeStatus{
+		Name: c.nodeName(n),
+		Type: n.GetTypeName(),
+		IP:   string(n.GetIP()),
+	}
+
+	client := newNodeClient(c, s.IP)
+	defer client.Close()
+
+	if _, err := client.OutputCtx(ctx, "true"); err != nil {
+		s.Error = err.Error()
+		return s
+	}
+
+	s.Reachable = true
+
+	if s.Type == "lb" {
+		s.HAProxy = serviceState(ctx, client, "haproxy")
+		s.Keepalived = serviceState(ctx, client, "keepalived")
+
+		vip := c.NewConfig.Cluster.Nodes.LoadBalancer.VIP
+		if vip != "" {
+			out, err := client.OutputCtx(ctx, "ip", "-o", "address", "show")
+			s.HoldsVIP = err == nil && strings.Contains(string(out), fmt.Sprintf("inet %s/", vip))
+		}
+
+		return s
+	}
+
+	kubelet, runtime := c.nodeServices(n)
+	s.Kubelet = serviceState(ctx, client, kubelet)
+	s.ContainerRuntime = serviceState(ctx, client, runtime)
+
+	return s
+}
+
+// nodeServices returns names of the services that run the kubelet and the
+// container runtime on the given node. K3s runs both within its own
+// service.
+func (c *Cluster) nodeServices(n config.Instance) (kubelet string, runtime string) {
+	if c.NewConfig.Kubernetes.Manager != config.ManagerK3s {
+		return "kubelet", "containerd"
+	}
+
+	if n.GetTypeName() == "master" {
+		return "k3s", "k3s"
+	}
+
+	return "k3s-agent", "k3s-agent"
+}
+
+// serviceState returns the state of the systemd service. Note that
+// systemctl exits with a non-zero code if the service is not active,
+// therefore the state is read from the output regardless of the error.
+func serviceState(ctx context.Context, client nodeClient, service string) string {
+	out, _ := client.OutputCtx(ctx, "systemctl", "is-active", service)
+
+	state := strings.TrimSpace(string(out))
+	if state == "" {
+		return "unknown"
+	}
+
+	return state
+}
+
+// kubeNode contains fields of the Kubernetes node object that are
+// relevant for the cluster status and node maintenance.
+type kubeNode struct {
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+
+	Spec struct {
+		Taints []kubeTaint `json:"taints"`
+	} `json:"spec"`
+
+	Status struct {
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+
+		NodeInfo struct {
+			KubeletVersion string `json:"kubeletVersion"`
+		} `json:"nodeInfo"`
+	} `json:"status"`
+}
+
+// kubeTaint is a taint of the Kubernetes node.
+type kubeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
+}
+
+func (t kubeTaint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// ready returns the status of the node's Ready condition.
+func (n kubeNode) ready() string {
+	for _, c := range n.Status.Conditions {
+		if c.Type == "Ready" {
+			return c.Status
+		}
+	}
+
+	return NODE_UNKNOWN
+}
+
+// readKubeNodes returns Kubernetes nodes mapped by their names.
+func readKubeNodes(ctx context.Context, c *Cluster) (map[string]kubeNode, error) {
+	out, err := kubeNodes(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Items []kubeNode `json:"items"`
+	}
+
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parse Kubernetes nodes: %v", err)
+	}
+
+	nodes := make(map[string]kubeNode, len(list.Items))
+	for _, n := range list.Items {
+		nodes[n.Metadata.Name] = n
+	}
+
+	return nodes, nil
+}
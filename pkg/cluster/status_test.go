@@ -0,0 +1,250 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MusicDin/kubitect/pkg/models/config"
+	"github.com/MusicDin/kubitect/pkg/models/infra"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nodeClientMock returns predefined outputs of commands. Commands without
+// predefined output fail, unless they have a predefined exit code.
+type nodeClientMock struct {
+	outputs   map[string]string
+	exitCodes map[string]int
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+// exitErrorMock is returned by the nodeClientMock for commands that exit
+// with a predefined exit code.
+type exitErrorMock struct {
+	code int
+}
+
+func (e exitErrorMock) Error() string {
+	return fmt.Sprintf("exited with status %d", e.code)
+}
+
+func (e exitErrorMock) ExitStatus() int {
+	return e.code
+}
+
+func (m *nodeClientMock) SetStdin(stdin io.Reader) {
+	m.stdin = stdin
+}
+
+func (m *nodeClientMock) SetStdout(stdout io.Writer) {
+	m.stdout = stdout
+}
+
+func (m *nodeClientMock) SetStderr(stderr io.Writer) {
+	m.stderr = stderr
+}
+
+func (m *nodeClientMock) RunCtx(ctx context.Context, command string, args ...string) error {
+	cmd := strings.Join(append([]string{command}, args...), " ")
+
+	if code, ok := m.exitCodes[cmd]; ok {
+		fmt.Fprintf(m.stderr, "%s: exit %d\n", cmd, code)
+		return exitErrorMock{code}
+	}
+
+	out, err := m.OutputCtx(ctx, command, args...)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.stdout.Write(out)
+	return err
+}
+
+func (m *nodeClientMock) OutputCtx(ctx context.Context, command string, args ...string) ([]byte, error) {
+	cmd := strings.Join(append([]string{command}, args...), " ")
+
+	out, ok := m.outputs[cmd]
+	if !ok {
+		return nil, fmt.Errorf("command %q failed", cmd)
+	}
+
+	return []byte(out), nil
+}
+
+func (m *nodeClientMock) Close() error {
+	return nil
+}
+
+// mockStatus replaces node clients and Kubernetes nodes with the given
+// mocks for the duration of the test.
+func mockStatus(t *testing.T, clients map[string]nodeClientMock, nodes string) {
+	t.Helper()
+
+	tmpClient := newNodeClient
+	tmpNodes := kubeNodes
+
+	newNodeClient = func(c *Cluster, ip string) nodeClient {
+		m := clients[ip]
+		return &m
+	}
+
+	kubeNodes = func(ctx context.Context, c *Cluster) ([]byte, error) {
+		if nodes == "" {
+			return nil, fmt.Errorf("kubectl not found")
+		}
+
+		return []byte(nodes), nil
+	}
+
+	t.Cleanup(func() {
+		newNodeClient = tmpClient
+		kubeNodes = tmpNodes
+	})
+}
+
+func mockStatusCluster(t *testing.T) *ClusterMock {
+	c := MockCluster(t)
+
+	c.NewConfig.Cluster.Nodes.LoadBalancer.VIP = "10.10.0.5"
+	c.InfraConfig = &infra.Config{
+		Nodes: config.Nodes{
+			Master: config.Master{
+				Instances: []config.MasterInstance{{Id: "1", IP: "10.10.0.10"}},
+			},
+			Worker: config.Worker{
+				Instances: []config.WorkerInstance{{Id: "1", IP: "10.10.0.20"}},
+			},
+			LoadBalancer: config.LB{
+				Instances: []config.LBInstance{
+					{Id: "1", IP: "10.10.0.30"},
+					{Id: "2", IP: "10.10.0.31"},
+				},
+			},
+		},
+	}
+
+	return c
+}
+
+func TestStatus(t *testing.T) {
+	c := mockStatusCluster(t)
+	name := c.Name
+
+	mockStatus(t, map[string]nodeClientMock{
+		"10.10.0.10": {outputs: map[string]string{
+			"true":                           "",
+			"systemctl is-active kubelet":    "active\n",
+			"systemctl is-active containerd": "active\n",
+		}},
+		"10.10.0.20": {outputs: map[string]string{
+			"true":                           "",
+			"systemctl is-active kubelet":    "failed\n",
+			"systemctl is-active containerd": "active\n",
+		}},
+		"10.10.0.30": {outputs: map[string]string{
+			"true":                           "",
+			"systemctl is-active haproxy":    "active\n",
+			"systemctl is-active keepalived": "active\n",
+			"ip -o address show":             "2: eth0    inet 10.10.0.30/24 brd 10.10.0.255\n2: eth0    inet 10.10.0.5/32 scope global eth0\n",
+		}},
+		// Second load balancer is unreachable.
+		"10.10.0.31": {},
+	}, fmt.Sprintf(`{"items": [
+		{
+			"metadata": {"name": "%[1]s-master-1"},
+			"status": {
+				"conditions": [{"type": "Ready", "status": "True"}],
+				"nodeInfo": {"kubeletVersion": "v1.28.6"}
+			}
+		},
+		{
+			"metadata": {"name": "%[1]s-worker-1"},
+			"status": {
+				"conditions": [{"type": "Ready", "status": "False"}],
+				"nodeInfo": {"kubeletVersion": "v1.28.6"}
+			}
+		}
+	]}`, name))
+
+	s := c.Status(time.Second)
+	assert.Equal(t, name, s.Cluster)
+	assert.Equal(t, "10.10.0.5", s.VIP)
+	assert.Equal(t, name+"-lb-1", s.VIPHolder)
+	assert.Empty(t, s.KubernetesError)
+	require.Len(t, s.Nodes, 4)
+
+	assert.Equal(t, NodeStatus{
+		Name:             name + "-master-1",
+		Type:             "master",
+		IP:               "10.10.0.10",
+		Reachable:        true,
+		Kubelet:          "active",
+		ContainerRuntime: "active",
+		Ready:            NODE_READY,
+		Version:          "v1.28.6",
+	}, s.Nodes[0])
+
+	assert.Equal(t, "failed", s.Nodes[1].Kubelet)
+	assert.Equal(t, NODE_NOT_READY, s.Nodes[1].Ready)
+
+	assert.Equal(t, NodeStatus{
+		Name:       name + "-lb-1",
+		Type:       "lb",
+		IP:         "10.10.0.30",
+		Reachable:  true,
+		HAProxy:    "active",
+		Keepalived: "active",
+		HoldsVIP:   true,
+	}, s.Nodes[2])
+
+	assert.False(t, s.Nodes[3].Reachable)
+	assert.Equal(t, `command "true" failed`, s.Nodes[3].Error)
+}
+
+func TestStatus_KubernetesUnavailable(t *testing.T) {
+	c := mockStatusCluster(t)
+	c.NewConfig.Kubernetes.Manager = config.ManagerK3s
+
+	mockStatus(t, map[string]nodeClientMock{
+		"10.10.0.10": {outputs: map[string]string{
+			"true":                    "",
+			"systemctl is-active k3s": "active\n",
+		}},
+		"10.10.0.20": {outputs: map[string]string{
+			"true": "",
+		}},
+	}, "")
+
+	s := c.Status(time.Second)
+	assert.Equal(t, "kubectl not found", s.KubernetesError)
+	assert.Empty(t, s.VIPHolder)
+	require.Len(t, s.Nodes, 4)
+
+	assert.Equal(t, "active", s.Nodes[0].Kubelet)
+	assert.Equal(t, "active", s.Nodes[0].ContainerRuntime)
+	assert.Equal(t, NODE_UNKNOWN, s.Nodes[0].Ready)
+
+	// State of the k3s agent cannot be determined.
+	assert.Equal(t, "unknown", s.Nodes[1].Kubelet)
+	assert.Equal(t, NODE_UNKNOWN, s.Nodes[1].Ready)
+}
+
+func TestOpenCluster(t *testing.T) {
+	c := MockCluster(t)
+
+	_, err := OpenCluster(c.ClusterMeta)
+	assert.EqualError(t, err, fmt.Sprintf("cluster %q has not been created yet", c.Name))
+
+	require.NoError(t, c.ApplyNewConfig())
+
+	_, err = OpenCluster(c.ClusterMeta)
+	assert.EqualError(t, err, fmt.Sprintf("infrastructure of cluster %q has not been provisioned yet", c.Name))
+}
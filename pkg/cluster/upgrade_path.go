@@ -0,0 +1,107 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/env"
+	"github.com/MusicDin/kubitect/pkg/utils/cmp"
+
+	"github.com/hashicorp/go-version"
+)
+
+// upgradePathEvents returns error events if the change of the Kubernetes
+// version is not a valid upgrade path. Changes that are already reported
+// as errors are skipped.
+func (c *Cluster) upgradePathEvents(events event.Events) event.Events {
+	if c.AppliedConfig == nil {
+		return nil
+	}
+
+	var uEvents event.Events
+	for _, e := range events {
+		if e.Rule.Type == event.Error || e.Change.Path != "kubernetes.version" {
+			continue
+		}
+
+		from := string(c.AppliedConfig.Kubernetes.Version)
+		to := string(c.NewConfig.Kubernetes.Version)
+
+		if err := validateUpgradePath(from, to); err != nil {
+			uEvents = append(uEvents, event.Event{
+				Rule: event.Rule{
+					Type:            event.Error,
+					MatchChangeType: cmp.Modify,
+					Message:         err.Error(),
+				},
+				Change:             e.Change,
+				MatchedChangePaths: e.MatchedChangePaths,
+			})
+		}
+	}
+
+	return uEvents
+}
+
+// validateUpgradePath ensures that the Kubernetes version can be upgraded
+// from one version to the other. Downgrades and upgrades that skip minor
+// versions are not allowed. In the latter case, the error suggests the
+// intermediate versions to upgrade through.
+func validateUpgradePath(from string, to string) error {
+	vFrom, err := version.NewVersion(from)
+	if err != nil {
+		return fmt.Errorf("invalid applied Kubernetes version %q: %v", from, err)
+	}
+
+	vTo, err := version.NewVersion(to)
+	if err != nil {
+		return fmt.Errorf("invalid Kubernetes version %q: %v", to, err)
+	}
+
+	if vTo.LessThan(vFrom) {
+		return fmt.Errorf("Downgrading Kubernetes from %s to %s is not supported.", from, to)
+	}
+
+	sFrom := vFrom.Segments()
+	sTo := vTo.Segments()
+
+	if sFrom[0] != sTo[0] {
+		return fmt.Errorf("Upgrading Kubernetes from %s to %s is not supported, because major versions differ.", from, to)
+	}
+
+	if sTo[1]-sFrom[1] <= 1 {
+		return nil
+	}
+
+	var path []string
+	for minor := sFrom[1] + 1; minor < sTo[1]; minor++ {
+		path = append(path, latestPatchVersion(sFrom[0], minor))
+	}
+
+	return fmt.Errorf("Upgrading Kubernetes from %s to %s skips minor versions. Upgrade through the following versions first (one upgrade each): %s.", from, to, strings.Join(path, ", "))
+}
+
+// latestPatchVersion returns the latest supported patch version of the
+// given minor Kubernetes version. If the minor version is not supported,
+// the patch version is replaced with "x" (e.g. v1.26.x).
+func latestPatchVersion(major int, minor int) string {
+	for _, r := range env.ProjectK8sVersions {
+		_, max, ok := strings.Cut(strings.ReplaceAll(r, " ", ""), "-")
+		if !ok {
+			continue
+		}
+
+		v, err := version.NewVersion(max)
+		if err != nil {
+			continue
+		}
+
+		s := v.Segments()
+		if s[0] == major && s[1] == minor {
+			return max
+		}
+	}
+
+	return fmt.Sprintf("v%d.%d.x", major, minor)
+}
@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/MusicDin/kubitect/pkg/cluster/event"
+	"github.com/MusicDin/kubitect/pkg/models/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUpgradePath(t *testing.T) {
+	assert.NoError(t, validateUpgradePath("v1.27.5", "v1.27.14"))
+	assert.NoError(t, validateUpgradePath("v1.27.5", "v1.28.0"))
+	assert.NoError(t, validateUpgradePath("v1.28.6", "v1.28.6"))
+}
+
+func TestValidateUpgradePath_Downgrade(t *testing.T) {
+	err := validateUpgradePath("v1.28.6", "v1.28.5")
+	assert.EqualError(t, err, "Downgrading Kubernetes from v1.28.6 to v1.28.5 is not supported.")
+
+	err = validateUpgradePath("v1.28.6", "v1.27.14")
+	assert.EqualError(t, err, "Downgrading Kubernetes from v1.28.6 to v1.27.14 is not supported.")
+}
+
+func TestValidateUpgradePath_SkipMinor(t *testing.T) {
+	err := validateUpgradePath("v1.27.5", "v1.29.1")
+	assert.EqualError(t, err, "Upgrading Kubernetes from v1.27.5 to v1.29.1 skips minor versions. Upgrade through the following versions first (one upgrade each): v1.28.10.")
+
+	// Unsupported intermediate versions are suggested without patch.
+	err = validateUpgradePath("v1.24.0", "v1.27.1")
+	assert.ErrorContains(t, err, "v1.25.x, v1.26.x.")
+}
+
+func TestValidateUpgradePath_Invalid(t *testing.T) {
+	assert.ErrorContains(t, validateUpgradePath("invalid", "v1.28.0"), "invalid applied Kubernetes version")
+	assert.ErrorContains(t, validateUpgradePath("v1.28.0", "invalid"), "invalid Kubernetes version")
+	assert.ErrorContains(t, validateUpgradePath("v1.28.0", "v2.0.0"), "major versions differ")
+}
+
+func TestEvents_UpgradePath(t *testing.T) {
+	c := MockCluster(t)
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.27.5")
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.29.1")
+
+	events, err := c.events(UPGRADE)
+	require.NoError(t, err)
+
+	errs := events.FilterByRuleType(event.Error)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Rule.Message, "v1.28.10")
+	assert.Equal(t, "kubernetes.version", errs[0].Change.Path)
+}
+
+func TestEvents_UpgradePath_NotAllowedChange(t *testing.T) {
+	c := MockCluster(t)
+	require.NoError(t, c.ApplyNewConfig())
+	require.NoError(t, c.Sync())
+
+	c.NewConfig.Kubernetes.Version = config.KubernetesVersion("v1.27.5")
+
+	// Version change is already reported as an error by the scale
+	// action, therefore the invalid upgrade path is not reported again.
+	events, err := c.events(SCALE)
+	require.NoError(t, err)
+	assert.Len(t, events.FilterByRuleType(event.Error), 1)
+}
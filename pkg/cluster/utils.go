@@ -1,8 +1,13 @@
 package cluster
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/MusicDin/kubitect/pkg/utils/file"
 	v "github.com/MusicDin/kubitect/pkg/utils/validation"
@@ -38,6 +43,18 @@ func readConfigIfExists[T v.Validatable](path string, model T) (*T, error) {
 	return file.ReadYaml(path, model)
 }
 
+// resolveConfigFile returns an absolute path of the file referenced in the
+// configuration file on the given path. Relative paths are resolved against
+// the directory of the configuration file, while absolute paths and paths
+// relative to the home directory are returned unchanged.
+func resolveConfigFile(cfgPath string, path string) (string, error) {
+	if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	return filepath.Abs(filepath.Join(filepath.Dir(cfgPath), path))
+}
+
 // validateConfig validates provided configuration file.
 func validateConfig[T v.Validatable](config T) []error {
 	var errs []error
@@ -54,3 +71,19 @@ func validateConfig[T v.Validatable](config T) []error {
 
 	return errs
 }
+
+// fileHash returns SHA-256 hash of the file on the given path. If file does
+// not exist, an empty string is returned.
+func fileHash(path string) (string, error) {
+	if !file.Exists(path) {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("hash file %q: %v", path, err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -35,6 +35,7 @@ var ProjectApplyActions = [...]string{
 	"create",
 	"upgrade",
 	"scale",
+	"scale-upgrade",
 }
 
 // ProjectK8sVersions define supported Kubernetes versions.
@@ -83,13 +83,37 @@ func (os OSSource) Validate() error {
 }
 
 type NodeTemplateSSH struct {
-	AddToKnownHosts bool `yaml:"addToKnownHosts"`
-	PrivateKeyPath  File `yaml:"privateKeyPath,omitempty"`
+	AddToKnownHosts bool       `yaml:"addToKnownHosts"`
+	PrivateKeyPath  File       `yaml:"privateKeyPath,omitempty"`
+	KeyType         SshKeyType `yaml:"keyType,omitempty"`
+	KeyBits         int        `yaml:"keyBits,omitempty"`
 }
 
 func (ssh NodeTemplateSSH) Validate() error {
-	return v.Struct(&ssh)
-	// v.Field(&ssh.PrivateKeyPath, v.Skip()),
+	return v.Struct(&ssh,
+		// v.Field(&ssh.PrivateKeyPath, v.Skip()),
+		v.Field(&ssh.KeyType),
+		v.Field(&ssh.KeyBits,
+			v.OmitEmpty(),
+			v.Min(2048).When(ssh.KeyType == "" || ssh.KeyType == RSA),
+			v.Max(16384).When(ssh.KeyType == "" || ssh.KeyType == RSA),
+			v.OneOf(256, 384, 521).When(ssh.KeyType == ECDSA),
+			v.Fail().When(ssh.KeyType == ED25519).Errorf("Field '{.Field}' cannot be set when key type is set to '%v'.", ED25519),
+		),
+	)
+}
+
+// SshKeyType is a type of the SSH key generated for the cluster.
+type SshKeyType string
+
+const (
+	RSA     SshKeyType = "rsa"
+	ECDSA   SshKeyType = "ecdsa"
+	ED25519 SshKeyType = "ed25519"
+)
+
+func (t SshKeyType) Validate() error {
+	return v.Var(t, v.OmitEmpty(), v.OneOf(RSA, ECDSA, ED25519))
 }
 
 type CpuMode string
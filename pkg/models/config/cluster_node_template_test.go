@@ -69,6 +69,20 @@ func TestNodeTemplateSSH(t *testing.T) {
 	assert.NoError(t, nts2.Validate())
 }
 
+func TestNodeTemplateSSH_KeyType(t *testing.T) {
+	assert.NoError(t, NodeTemplateSSH{KeyType: RSA}.Validate())
+	assert.NoError(t, NodeTemplateSSH{KeyType: ECDSA}.Validate())
+	assert.NoError(t, NodeTemplateSSH{KeyType: ED25519}.Validate())
+	assert.NoError(t, NodeTemplateSSH{KeyBits: 3072}.Validate())
+	assert.NoError(t, NodeTemplateSSH{KeyType: RSA, KeyBits: 4096}.Validate())
+	assert.NoError(t, NodeTemplateSSH{KeyType: ECDSA, KeyBits: 384}.Validate())
+	assert.Error(t, NodeTemplateSSH{KeyType: "dsa"}.Validate())
+	assert.Error(t, NodeTemplateSSH{KeyBits: 1024}.Validate())
+	assert.Error(t, NodeTemplateSSH{KeyType: RSA, KeyBits: 32768}.Validate())
+	assert.Error(t, NodeTemplateSSH{KeyType: ECDSA, KeyBits: 4096}.Validate())
+	assert.Error(t, NodeTemplateSSH{KeyType: ED25519, KeyBits: 256}.Validate())
+}
+
 func TestCpuMode(t *testing.T) {
 	assert.NoError(t, CpuMode("custom").Validate())
 	assert.NoError(t, CpuMode(HOST_PASSTHROUGH).Validate())
@@ -15,6 +15,10 @@ type Instance interface {
 	GetID() string
 	GetIP() IPv4
 	GetMAC() MAC
+	GetHost() string
+	GetCPU() VCpu
+	GetRAM() GB
+	GetMainDiskSize() GB
 }
 
 type Nodes struct {
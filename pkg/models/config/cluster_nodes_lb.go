@@ -126,6 +126,22 @@ func (i LBInstance) GetMAC() MAC {
 	return i.MAC
 }
 
+func (i LBInstance) GetHost() string {
+	return i.Host
+}
+
+func (i LBInstance) GetCPU() VCpu {
+	return i.CPU
+}
+
+func (i LBInstance) GetRAM() GB {
+	return i.RAM
+}
+
+func (i LBInstance) GetMainDiskSize() GB {
+	return i.MainDiskSize
+}
+
 func (i LBInstance) Validate() error {
 	return v.Struct(&i,
 		v.Field(&i.Id, v.NotEmpty(), v.AlphaNumericHypUS()),
@@ -53,7 +53,7 @@ func (m *Master) SetDefaults() {
 		m.Instances[i].CPU = defaults.Default(m.Instances[i].CPU, m.Default.CPU)
 		m.Instances[i].RAM = defaults.Default(m.Instances[i].RAM, m.Default.RAM)
 		m.Instances[i].MainDiskSize = defaults.Default(m.Instances[i].MainDiskSize, m.Default.MainDiskSize)
-		m.Instances[i].DataDisks = append(m.Default.DataDisks, m.Instances[i].DataDisks...)
+		m.Instances[i].DataDisks = withDefaultDataDisks(m.Default.DataDisks, m.Instances[i].DataDisks)
 	}
 }
 
@@ -87,6 +87,22 @@ func (i MasterInstance) GetMAC() MAC {
 	return i.MAC
 }
 
+func (i MasterInstance) GetHost() string {
+	return i.Host
+}
+
+func (i MasterInstance) GetCPU() VCpu {
+	return i.CPU
+}
+
+func (i MasterInstance) GetRAM() GB {
+	return i.RAM
+}
+
+func (i MasterInstance) GetMainDiskSize() GB {
+	return i.MainDiskSize
+}
+
 func (i MasterInstance) Validate() error {
 	defer v.RemoveCustomValidator(VALID_POOL)
 
@@ -155,3 +155,29 @@ func TestMaster_DefaultDataDisks(t *testing.T) {
 	assert.Equal(t, m.Default.DataDisks, m.Instances[1].DataDisks)
 	assert.Equal(t, m.Default.DataDisks, m.Instances[2].DataDisks)
 }
+
+func TestMaster_DefaultDataDisks_Idempotent(t *testing.T) {
+	defDisks := []DataDisk{
+		{Name: "def-disk1", Size: GB(42)},
+		{Name: "def-disk2", Size: GB(42)},
+	}
+
+	n := Master{
+		Default: MasterDefault{
+			DataDisks: defDisks,
+		},
+		Instances: []MasterInstance{
+			{Id: "id1", DataDisks: []DataDisk{{Name: "def-disk2", Size: GB(10)}}},
+			{Id: "id2"},
+			{Id: "id3"},
+		},
+	}
+
+	defaults.Assign(&n)
+	defaults.Assign(&n)
+
+	assert.NoError(t, n.Validate())
+	assert.Equal(t, []DataDisk{defDisks[0], {Name: "def-disk2", Size: GB(10)}}, n.Instances[0].DataDisks)
+	assert.Equal(t, defDisks, n.Instances[1].DataDisks)
+	assert.Equal(t, defDisks, n.Default.DataDisks)
+}
@@ -48,7 +48,7 @@ func (w *Worker) SetDefaults() {
 		w.Instances[i].CPU = defaults.Default(w.Instances[i].CPU, w.Default.CPU)
 		w.Instances[i].RAM = defaults.Default(w.Instances[i].RAM, w.Default.RAM)
 		w.Instances[i].MainDiskSize = defaults.Default(w.Instances[i].MainDiskSize, w.Default.MainDiskSize)
-		w.Instances[i].DataDisks = append(w.Default.DataDisks, w.Instances[i].DataDisks...)
+		w.Instances[i].DataDisks = withDefaultDataDisks(w.Default.DataDisks, w.Instances[i].DataDisks)
 	}
 }
 
@@ -82,6 +82,22 @@ func (i WorkerInstance) GetMAC() MAC {
 	return i.MAC
 }
 
+func (i WorkerInstance) GetHost() string {
+	return i.Host
+}
+
+func (i WorkerInstance) GetCPU() VCpu {
+	return i.CPU
+}
+
+func (i WorkerInstance) GetRAM() GB {
+	return i.RAM
+}
+
+func (i WorkerInstance) GetMainDiskSize() GB {
+	return i.MainDiskSize
+}
+
 func (i WorkerInstance) Validate() error {
 	defer v.RemoveCustomValidator(VALID_POOL)
 
@@ -144,3 +144,28 @@ func TestWorker_DefaultDataDisks(t *testing.T) {
 	assert.Equal(t, w.Default.DataDisks, w.Instances[1].DataDisks)
 	assert.Equal(t, w.Default.DataDisks, w.Instances[2].DataDisks)
 }
+
+func TestWorker_DefaultDataDisks_Idempotent(t *testing.T) {
+	defDisks := []DataDisk{
+		{Name: "def-disk1", Size: GB(42)},
+		{Name: "def-disk2", Size: GB(42)},
+	}
+
+	n := Worker{
+		Default: WorkerDefault{
+			DataDisks: defDisks,
+		},
+		Instances: []WorkerInstance{
+			{Id: "id1", DataDisks: []DataDisk{{Name: "def-disk2", Size: GB(10)}}},
+			{Id: "id2"},
+		},
+	}
+
+	defaults.Assign(&n)
+	defaults.Assign(&n)
+
+	assert.NoError(t, n.Validate())
+	assert.Equal(t, []DataDisk{defDisks[0], {Name: "def-disk2", Size: GB(10)}}, n.Instances[0].DataDisks)
+	assert.Equal(t, defDisks, n.Instances[1].DataDisks)
+	assert.Equal(t, defDisks, n.Default.DataDisks)
+}
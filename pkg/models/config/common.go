@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"slices"
 	"strings"
 
 	v "github.com/MusicDin/kubitect/pkg/utils/validation"
@@ -115,6 +116,21 @@ func (d DataDisk) Validate() error {
 	)
 }
 
+// withDefaultDataDisks returns the given data disks preceded by the
+// default data disks. Default data disks are merged by name, therefore a
+// default disk is omitted if a disk with the same name is already given.
+// This ensures that defaults can be applied repeatedly.
+func withDefaultDataDisks(defaults []DataDisk, disks []DataDisk) []DataDisk {
+	var res []DataDisk
+	for _, d := range defaults {
+		if !slices.ContainsFunc(disks, func(dd DataDisk) bool { return dd.Name == d.Name }) {
+			res = append(res, d)
+		}
+	}
+
+	return append(res, disks...)
+}
+
 type Version string
 
 func (ver Version) Validate() error {
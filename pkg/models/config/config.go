@@ -19,6 +19,7 @@ type Config struct {
 	Cluster    Cluster    `yaml:"cluster"`
 	Kubernetes Kubernetes `yaml:"kubernetes"`
 	Addons     Addons     `yaml:"addons,omitempty"`
+	PolicyFile File       `yaml:"policyFile,omitempty"`
 }
 
 func (c Config) Validate() error {
@@ -36,6 +37,7 @@ func (c Config) Validate() error {
 		v.Field(&c.Cluster, v.NotEmpty().Error("Configuration must contain '{.Field}' section.")),
 		v.Field(&c.Kubernetes, v.NotEmpty().Error("Configuration must contain '{.Field}' section.")),
 		v.Field(&c.Addons),
+		v.Field(&c.PolicyFile, v.OmitEmpty()),
 	)
 }
 
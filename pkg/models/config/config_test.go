@@ -171,3 +171,10 @@ func TestConfig_MissingHostName(t *testing.T) {
 
 	assert.EqualError(t, defaults.Assign(&cfg).Validate(), "Field 'name' is required and cannot be empty.")
 }
+
+func TestConfig_PolicyFile(t *testing.T) {
+	cfg := MockConfig(t)
+	cfg.PolicyFile = "missing.yaml"
+
+	assert.ErrorContains(t, defaults.Assign(&cfg).Validate(), "missing.yaml")
+}
@@ -14,6 +14,7 @@ type Kubernetes struct {
 	Manager       KubernetesManager `yaml:"manager"`
 	DnsMode       DnsMode           `yaml:"dnsMode"`
 	NetworkPlugin NetworkPlugin     `yaml:"networkPlugin"`
+	Upgrade       Upgrade           `yaml:"upgrade"`
 	Other         Other             `yaml:"other"`
 }
 
@@ -22,6 +23,7 @@ func (k Kubernetes) Validate() error {
 		v.Field(&k.Version, v.NotEmpty(), v.VSemVer()),
 		v.Field(&k.DnsMode, v.NotEmpty()),
 		v.Field(&k.NetworkPlugin, v.NotEmpty()),
+		v.Field(&k.Upgrade),
 		v.Field(&k.Other),
 	)
 }
@@ -95,6 +97,49 @@ func (p NetworkPlugin) Validate() error {
 	return v.Var(p, v.OneOf(CALICO, CILIUM, FLANNEL, KUBE_ROUTER))
 }
 
+// Upgrade defines how the cluster nodes are upgraded.
+type Upgrade struct {
+	Strategy        UpgradeStrategy `yaml:"strategy"`
+	MasterBatchSize BatchSize       `yaml:"masterBatchSize"`
+	WorkerBatchSize BatchSize       `yaml:"workerBatchSize"`
+}
+
+func (u Upgrade) Validate() error {
+	return v.Struct(&u,
+		v.Field(&u.Strategy),
+		v.Field(&u.MasterBatchSize),
+		v.Field(&u.WorkerBatchSize),
+	)
+}
+
+func (u *Upgrade) SetDefaults() {
+	u.Strategy = defaults.Default(u.Strategy, ALL_AT_ONCE)
+	u.MasterBatchSize = defaults.Default(u.MasterBatchSize, BatchSize(1))
+	u.WorkerBatchSize = defaults.Default(u.WorkerBatchSize, BatchSize(1))
+}
+
+type UpgradeStrategy string
+
+const (
+	// ALL_AT_ONCE upgrades all nodes with a single playbook run.
+	ALL_AT_ONCE UpgradeStrategy = "all-at-once"
+
+	// ROLLING upgrades master nodes and then worker nodes in batches,
+	// and verifies the health of each batch before continuing.
+	ROLLING UpgradeStrategy = "rolling"
+)
+
+func (s UpgradeStrategy) Validate() error {
+	return v.Var(s, v.OmitEmpty(), v.OneOf(ALL_AT_ONCE, ROLLING))
+}
+
+// BatchSize is the number of nodes that are upgraded at the same time.
+type BatchSize int
+
+func (s BatchSize) Validate() error {
+	return v.Var(s, v.OmitEmpty(), v.Min(1))
+}
+
 type Other struct {
 	AutoRenewCertificates bool `yaml:"autoRenewCertificates"`
 	MergeKubeconfig       bool `yaml:"mergeKubeconfig"`
@@ -33,6 +33,26 @@ func TestNetworkPlugin(t *testing.T) {
 	assert.NoError(t, CILIUM.Validate())
 }
 
+func TestUpgradeStrategy(t *testing.T) {
+	assert.NoError(t, UpgradeStrategy("").Validate())
+	assert.NoError(t, ALL_AT_ONCE.Validate())
+	assert.NoError(t, ROLLING.Validate())
+	assert.Error(t, UpgradeStrategy("wrong").Validate())
+}
+
+func TestUpgrade(t *testing.T) {
+	u := Upgrade{
+		Strategy:        ROLLING,
+		MasterBatchSize: 1,
+		WorkerBatchSize: 3,
+	}
+
+	assert.NoError(t, u.Validate())
+
+	u.WorkerBatchSize = -1
+	assert.ErrorContains(t, u.Validate(), "Minimum value for field 'workerBatchSize' is 1")
+}
+
 func TestKubernetes_Empty(t *testing.T) {
 	k8s := Kubernetes{}
 	assert.ErrorContains(t, k8s.Validate(), "Field 'version' is required and cannot be empty.")
@@ -60,3 +80,11 @@ func TestDefault(t *testing.T) {
 	assert.NoError(t, defaults.Set(&k))
 	assert.Equal(t, COREDNS, k.DnsMode)
 }
+
+func TestUpgrade_Default(t *testing.T) {
+	k := Kubernetes{}
+	assert.NoError(t, defaults.Set(&k))
+	assert.Equal(t, ALL_AT_ONCE, k.Upgrade.Strategy)
+	assert.Equal(t, BatchSize(1), k.Upgrade.MasterBatchSize)
+	assert.Equal(t, BatchSize(1), k.Upgrade.WorkerBatchSize)
+}
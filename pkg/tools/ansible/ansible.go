@@ -17,6 +17,7 @@ import (
 type Playbook struct {
 	Inventory  string
 	Tags       []string
+	Limit      []string
 	User       string
 	PrivateKey string
 	Become     bool
@@ -80,6 +81,7 @@ func (a *ansible) Exec(pb Playbook) error {
 	playbookOptions := &playbook.AnsiblePlaybookOptions{
 		Inventory: pb.Inventory,
 		Tags:      strings.Join(pb.Tags, ","),
+		Limit:     strings.Join(pb.Limit, ","),
 		Forks:     "50",
 	}
 
@@ -50,6 +50,10 @@ type Content struct {
 	title string
 	lines Lines
 
+	// Key under which the content is stored in a structured output.
+	// If empty, the key is derived from the title.
+	key string
+
 	// Print each line into a new Indents each line
 	linesIndent int
 
@@ -61,6 +65,13 @@ type Content struct {
 	compact bool
 }
 
+// WithKey sets the key under which the content is stored when the block
+// is printed in a structured output format.
+func (c Content) WithKey(key string) Content {
+	c.key = key
+	return c
+}
+
 func (c Content) format(s streams.OutputStream, color Color, indent int) []string {
 	if c.linesRequired && len(c.lines) == 0 {
 		return nil
@@ -1,10 +1,13 @@
 package ui
 
+import "strings"
+
 type (
 	ErrorBlock interface {
 		Block
 		Error() string
 		Severity() Level
+		Object() map[string]any
 	}
 
 	errorBlock struct {
@@ -21,6 +24,33 @@ func (e errorBlock) Severity() Level {
 	return e.severity
 }
 
+// Object returns a structured representation of the error block. Each
+// content is stored under a key derived from its title.
+func (e errorBlock) Object() map[string]any {
+	obj := map[string]any{
+		"severity": e.severity.String(),
+	}
+
+	for _, c := range e.content {
+		if c.linesRequired && len(c.lines) == 0 {
+			continue
+		}
+
+		key := c.key
+		if key == "" {
+			key = contentKey(c.title)
+		}
+
+		if c.compact {
+			obj[key] = strings.Join(c.lines, " ")
+		} else {
+			obj[key] = []string(c.lines)
+		}
+	}
+
+	return obj
+}
+
 func NewErrorBlock(level Level, content []Content) ErrorBlock {
 	return errorBlock{
 		severity: level,
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/MusicDin/kubitect/pkg/ui/streams"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat defines how the command output is printed.
+type OutputFormat string
+
+const (
+	// OutputText prints human-readable (colorized) output.
+	OutputText OutputFormat = "text"
+
+	// OutputJson prints each object as a single line JSON document.
+	OutputJson OutputFormat = "json"
+
+	// OutputYaml prints each object as a separate YAML document.
+	OutputYaml OutputFormat = "yaml"
+)
+
+var OutputFormats = []OutputFormat{
+	OutputText,
+	OutputJson,
+	OutputYaml,
+}
+
+// ToOutputFormat converts the given string into an output format. An empty
+// string is converted into a text format.
+func ToOutputFormat(format string) (OutputFormat, error) {
+	if format == "" {
+		return OutputText, nil
+	}
+
+	for _, f := range OutputFormats {
+		if string(f) == strings.ToLower(format) {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown output format: %s (valid formats: %v)", format, OutputFormats)
+}
+
+// IsStructured returns true if output format is machine-readable.
+func (f OutputFormat) IsStructured() bool {
+	return f == OutputJson || f == OutputYaml
+}
+
+func (f OutputFormat) String() string {
+	if f == "" {
+		return string(OutputText)
+	}
+
+	return string(f)
+}
+
+// Object is a structured output object. Kind identifies the type of
+// the object's data.
+type Object struct {
+	Kind string `json:"kind" yaml:"kind"`
+	Data any    `json:"data" yaml:"data"`
+}
+
+func Output() OutputFormat {
+	return GlobalUi().Output()
+}
+
+func (u *ui) Output() OutputFormat {
+	if u.output == "" {
+		return OutputText
+	}
+
+	return u.output
+}
+
+func PrintObject(kind string, data any) error {
+	return GlobalUi().PrintObject(kind, data)
+}
+
+// PrintObject prints an object of the given kind to the standard output
+// when the structured output format is used. In text mode, nothing is
+// printed, since the caller is expected to print a human-readable
+// representation of the data instead.
+func (u *ui) PrintObject(kind string, data any) error {
+	var out []byte
+	var err error
+
+	obj := Object{
+		Kind: kind,
+		Data: data,
+	}
+
+	switch u.Output() {
+	case OutputJson:
+		out, err = json.Marshal(obj)
+		out = append(out, '\n')
+	case OutputYaml:
+		out, err = yaml.Marshal(obj)
+		out = append([]byte("---\n"), out...)
+	default:
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("print %s object: %v", kind, err)
+	}
+
+	_, err = u.streams.Out().File().Write(out)
+	return err
+}
+
+// structuredStreams redirects the output stream to the error stream, so
+// that the standard output contains only structured objects.
+type structuredStreams struct {
+	streams.Streams
+}
+
+func (s structuredStreams) Out() streams.OutputStream {
+	return s.Streams.Err()
+}
+
+// contentKey converts the content title into a key of a structured
+// object. For example, title "Config path:" is converted into
+// "configPath".
+func contentKey(title string) string {
+	words := strings.Fields(strings.TrimSuffix(strings.TrimSpace(title), ":"))
+
+	for i, w := range words {
+		r := []rune(strings.ToLower(w))
+		if i > 0 {
+			r[0] = unicode.ToUpper(r[0])
+		}
+
+		words[i] = string(r)
+	}
+
+	return strings.Join(words, "")
+}
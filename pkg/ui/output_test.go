@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToOutputFormat(t *testing.T) {
+	f, err := ToOutputFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, OutputText, f)
+
+	f, err = ToOutputFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, OutputJson, f)
+	assert.True(t, f.IsStructured())
+
+	f, err = ToOutputFormat("yaml")
+	require.NoError(t, err)
+	assert.Equal(t, OutputYaml, f)
+	assert.True(t, f.IsStructured())
+}
+
+func TestToOutputFormat_Invalid(t *testing.T) {
+	_, err := ToOutputFormat("xml")
+	assert.EqualError(t, err, "unknown output format: xml (valid formats: [text json yaml])")
+}
+
+func TestUi_PrintObject_Text(t *testing.T) {
+	ui := MockUi(t)
+
+	require.NoError(t, ui.PrintObject("Test", []string{"a"}))
+	assert.Empty(t, ui.ReadStdout(t))
+}
+
+func TestUi_PrintObject_Json(t *testing.T) {
+	ui := MockUi(t, UiOptions{Output: OutputJson})
+
+	require.NoError(t, ui.PrintObject("Test", []string{"a", "b"}))
+	assert.Equal(t, "{\"kind\":\"Test\",\"data\":[\"a\",\"b\"]}\n", ui.ReadStdout(t))
+}
+
+func TestUi_PrintObject_Yaml(t *testing.T) {
+	ui := MockUi(t, UiOptions{Output: OutputYaml})
+
+	require.NoError(t, ui.PrintObject("Test", []string{"a", "b"}))
+	assert.Equal(t, "---\nkind: Test\ndata:\n    - a\n    - b\n", ui.ReadStdout(t))
+}
+
+func TestUi_Print_Structured(t *testing.T) {
+	ui := MockUi(t, UiOptions{Output: OutputJson})
+	ui.Print(INFO, "test")
+
+	// Text is redirected to the error stream.
+	assert.Empty(t, ui.ReadStdout(t))
+	assert.Equal(t, "test", ui.ReadStderr(t))
+}
+
+func TestUi_PrintBlockE_Structured(t *testing.T) {
+	ui := MockUi(t, UiOptions{Output: OutputJson})
+
+	eb := NewErrorBlock(WARN,
+		[]Content{
+			NewErrorLine("Warning type:", "Test"),
+			NewErrorSection("Config path:", "a.b", "a.c").WithKey("paths"),
+			NewErrorSection("Missing files:"),
+		},
+	)
+
+	ui.PrintBlockE(fmt.Errorf("test"))
+	ui.PrintBlockE(eb)
+
+	expect := `{"kind":"Error","data":{"error":"test","severity":"error"}}` + "\n" +
+		`{"kind":"Warning","data":{"paths":["a.b","a.c"],"severity":"warning","warningType":"Test"}}` + "\n"
+
+	assert.Equal(t, expect, ui.ReadStdout(t))
+	assert.Empty(t, ui.ReadStderr(t))
+}
+
+func TestContentKey(t *testing.T) {
+	assert.Equal(t, "error", contentKey("Error:"))
+	assert.Equal(t, "configPath", contentKey("Config path:"))
+	assert.Equal(t, "errorType", contentKey(" Error TYPE: "))
+}
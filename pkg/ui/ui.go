@@ -26,6 +26,7 @@ func GlobalUi(opts ...UiOptions) Ui {
 				instance.autoApprove = o.AutoApprove
 				instance.debug = o.Debug
 				instance.noColor = o.NoColor
+				instance.output = o.Output
 			}
 		})
 	}
@@ -42,10 +43,24 @@ const (
 	ERROR
 )
 
+func (l Level) String() string {
+	switch l {
+	case DEBUG:
+		return "debug"
+	case INFO:
+		return "info"
+	case WARN:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
 type UiOptions struct {
 	NoColor     bool
 	Debug       bool
 	AutoApprove bool
+	Output      OutputFormat
 }
 
 type (
@@ -55,9 +70,11 @@ type (
 		Printf(level Level, format string, args ...any)
 		Println(level Level, msg ...any)
 		PrintBlockE(err ...error)
+		PrintObject(kind string, data any) error
 
 		Streams() streams.Streams
 
+		Output() OutputFormat
 		HasColor() bool
 		Debug() bool
 		AutoApprove() bool
@@ -69,6 +86,7 @@ type (
 		noColor     bool
 		debug       bool
 		autoApprove bool
+		output      OutputFormat
 	}
 )
 
@@ -100,16 +118,22 @@ func Streams() streams.Streams {
 	return GlobalUi().Streams()
 }
 
+// Streams returns input and output streams. When the structured output
+// format is used, the output stream is redirected to the error stream.
 func (u *ui) Streams() streams.Streams {
+	if u.Output().IsStructured() {
+		return structuredStreams{u.streams}
+	}
+
 	return u.streams
 }
 
 func (u *ui) outputStream(level Level) streams.OutputStream {
 	switch level {
 	case ERROR, WARN:
-		return u.streams.Err()
+		return u.Streams().Err()
 	default:
-		return u.streams.Out()
+		return u.Streams().Out()
 	}
 }
 
@@ -222,9 +246,28 @@ func (u *ui) PrintBlockE(errs ...error) {
 			eb = NewErrorBlock(ERROR, content)
 		}
 
+		if u.Output().IsStructured() {
+			u.printErrorObject(eb)
+			continue
+		}
+
 		s := u.outputStream(eb.Severity())
 		c := u.outputColor(eb.Severity())
 
 		fmt.Fprintln(s.File(), eb.Format(s, c))
 	}
 }
+
+// printErrorObject prints the error block as a structured object. If the
+// object cannot be serialized, the error block is printed as text.
+func (u *ui) printErrorObject(eb ErrorBlock) {
+	kind := "Error"
+	if eb.Severity() == WARN {
+		kind = "Warning"
+	}
+
+	if err := u.PrintObject(kind, eb.Object()); err != nil {
+		s := u.outputStream(eb.Severity())
+		fmt.Fprintln(s.File(), eb.Format(s, Colors.NONE))
+	}
+}
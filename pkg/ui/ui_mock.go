@@ -36,6 +36,7 @@ func setUiOptions(ui *uiMock, uiOptions ...UiOptions) *uiMock {
 	ui.autoApprove = o.AutoApprove
 	ui.debug = o.Debug
 	ui.noColor = o.NoColor
+	ui.output = o.Output
 
 	return ui
 }
@@ -96,7 +97,7 @@ func MockGlobalTerminalUi(t *testing.T, uiOptions ...UiOptions) UiMock {
 func (ui *uiMock) ReadStdout(t *testing.T) string {
 	t.Helper()
 
-	f := ui.Streams().Out().File()
+	f := ui.streams.Out().File()
 	f.Seek(ui.errOffset, 0)
 
 	bytes, err := os.ReadFile(f.Name())
@@ -112,7 +113,7 @@ func (ui *uiMock) ReadStdout(t *testing.T) string {
 func (ui *uiMock) ReadStderr(t *testing.T) string {
 	t.Helper()
 
-	f := ui.Streams().Err().File()
+	f := ui.streams.Err().File()
 	f.Seek(ui.outOffset, 0)
 
 	bytes, err := os.ReadFile(f.Name())
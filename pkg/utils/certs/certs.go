@@ -0,0 +1,63 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse parses the first PEM encoded certificate in the given data.
+func Parse(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// Credentials are client credentials embedded in the kubeconfig. Both the
+// certificate and the key are base64 encoded PEM blocks.
+type Credentials struct {
+	CertificateData string `yaml:"client-certificate-data"`
+	KeyData         string `yaml:"client-key-data"`
+}
+
+// Certificate parses the client certificate.
+func (c Credentials) Certificate() (*x509.Certificate, error) {
+	data, err := base64.StdEncoding.DecodeString(c.CertificateData)
+	if err != nil {
+		return nil, fmt.Errorf("decode client certificate: %v", err)
+	}
+
+	return Parse(data)
+}
+
+// KubeconfigCredentials returns client credentials of the first user in
+// the given kubeconfig.
+func KubeconfigCredentials(kubeconfig []byte) (*Credentials, error) {
+	var cfg struct {
+		Users []struct {
+			User Credentials `yaml:"user"`
+		} `yaml:"users"`
+	}
+
+	if err := yaml.Unmarshal(kubeconfig, &cfg); err != nil {
+		return nil, fmt.Errorf("parse kubeconfig: %v", err)
+	}
+
+	if len(cfg.Users) == 0 || cfg.Users[0].User.CertificateData == "" {
+		return nil, fmt.Errorf("kubeconfig does not contain a client certificate")
+	}
+
+	return &cfg.Users[0].User, nil
+}
@@ -0,0 +1,137 @@
+package cmp
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+)
+
+// PatchOperation is a single operation of a JSON Patch (RFC 6902).
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+// object returns a serializable representation of the operation. Value is
+// omitted only for the remove operation, since other operations require it
+// even if it is null.
+func (o PatchOperation) object() any {
+	if o.Op == PatchRemove {
+		return struct {
+			Op   string `json:"op" yaml:"op"`
+			Path string `json:"path" yaml:"path"`
+		}{o.Op, o.Path}
+	}
+
+	return struct {
+		Op    string `json:"op" yaml:"op"`
+		Path  string `json:"path" yaml:"path"`
+		Value any    `json:"value" yaml:"value"`
+	}{o.Op, o.Path, o.Value}
+}
+
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.object())
+}
+
+func (o PatchOperation) MarshalYAML() (any, error) {
+	return o.object(), nil
+}
+
+// JsonPatch returns a JSON Patch (RFC 6902) that transforms value a into
+// value b. Both values are expected to be generic JSON documents, which
+// consist only of maps with string keys, slices of type []any and basic
+// values. Slices are compared by index.
+func JsonPatch(a any, b any) []PatchOperation {
+	return jsonPatch("", a, b, []PatchOperation{})
+}
+
+func jsonPatch(path string, a any, b any, ops []PatchOperation) []PatchOperation {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+
+		for _, k := range mapKeys(av, bv) {
+			p := path + "/" + escapePointer(k)
+
+			ai, aok := av[k]
+			bi, bok := bv[k]
+
+			switch {
+			case !bok:
+				ops = append(ops, PatchOperation{Op: PatchRemove, Path: p})
+			case !aok:
+				ops = append(ops, PatchOperation{Op: PatchAdd, Path: p, Value: bi})
+			default:
+				ops = jsonPatch(p, ai, bi, ops)
+			}
+		}
+
+		return ops
+
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+
+		for i := 0; i < min(len(av), len(bv)); i++ {
+			ops = jsonPatch(path+"/"+strconv.Itoa(i), av[i], bv[i], ops)
+		}
+
+		// Elements are removed from the end, so that indexes of the
+		// remaining elements do not change.
+		for i := len(av) - 1; i >= len(bv); i-- {
+			ops = append(ops, PatchOperation{Op: PatchRemove, Path: path + "/" + strconv.Itoa(i)})
+		}
+
+		for i := len(av); i < len(bv); i++ {
+			ops = append(ops, PatchOperation{Op: PatchAdd, Path: path + "/" + strconv.Itoa(i), Value: bv[i]})
+		}
+
+		return ops
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		ops = append(ops, PatchOperation{Op: PatchReplace, Path: path, Value: b})
+	}
+
+	return ops
+}
+
+// mapKeys returns sorted keys of both maps.
+func mapKeys(a, b map[string]any) []string {
+	var keys []string
+
+	for k := range a {
+		keys = append(keys, k)
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes the key, so that it can be used as a reference
+// token of a JSON pointer (RFC 6901).
+func escapePointer(key string) string {
+	key = strings.ReplaceAll(key, "~", "~0")
+	return strings.ReplaceAll(key, "/", "~1")
+}
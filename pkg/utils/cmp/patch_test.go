@@ -0,0 +1,85 @@
+package cmp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonPatch_NoChanges(t *testing.T) {
+	doc := map[string]any{"a": 1, "b": []any{"x"}}
+	assert.Empty(t, JsonPatch(doc, doc))
+}
+
+func TestJsonPatch_Map(t *testing.T) {
+	a := map[string]any{
+		"keep":    1,
+		"modify":  "old",
+		"remove":  true,
+		"a/b~c":   1,
+		"nested":  map[string]any{"value": 1},
+		"typeChg": "str",
+	}
+
+	b := map[string]any{
+		"keep":    1,
+		"modify":  "new",
+		"add":     map[string]any{"x": nil},
+		"a/b~c":   2,
+		"nested":  map[string]any{"value": 2},
+		"typeChg": []any{"str"},
+	}
+
+	expect := []PatchOperation{
+		{Op: PatchReplace, Path: "/a~1b~0c", Value: 2},
+		{Op: PatchAdd, Path: "/add", Value: map[string]any{"x": nil}},
+		{Op: PatchReplace, Path: "/modify", Value: "new"},
+		{Op: PatchReplace, Path: "/nested/value", Value: 2},
+		{Op: PatchRemove, Path: "/remove"},
+		{Op: PatchReplace, Path: "/typeChg", Value: []any{"str"}},
+	}
+
+	assert.Equal(t, expect, JsonPatch(a, b))
+}
+
+func TestJsonPatch_Slice(t *testing.T) {
+	a := map[string]any{"s": []any{1, 2, 3, 4}}
+	b := map[string]any{"s": []any{1, 5}}
+
+	expect := []PatchOperation{
+		{Op: PatchReplace, Path: "/s/1", Value: 5},
+		{Op: PatchRemove, Path: "/s/3"},
+		{Op: PatchRemove, Path: "/s/2"},
+	}
+
+	assert.Equal(t, expect, JsonPatch(a, b))
+
+	expect = []PatchOperation{
+		{Op: PatchReplace, Path: "/s/1", Value: 2},
+		{Op: PatchAdd, Path: "/s/2", Value: 3},
+		{Op: PatchAdd, Path: "/s/3", Value: 4},
+	}
+
+	assert.Equal(t, expect, JsonPatch(b, a))
+}
+
+func TestJsonPatch_Root(t *testing.T) {
+	expect := []PatchOperation{
+		{Op: PatchReplace, Path: "", Value: map[string]any{"a": 1}},
+	}
+
+	assert.Equal(t, expect, JsonPatch(nil, map[string]any{"a": 1}))
+}
+
+func TestPatchOperation_Marshal(t *testing.T) {
+	ops := []PatchOperation{
+		{Op: PatchRemove, Path: "/a"},
+		{Op: PatchReplace, Path: "/b", Value: nil},
+	}
+
+	out, err := json.Marshal(ops)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":null}]`, string(out))
+}
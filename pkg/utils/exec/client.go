@@ -1,21 +1,25 @@
 package exec
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
 )
 
 // Ensure all clients implement Client interface.
 var _ Client = localClient{}
-var _ Client = remoteClient{}
+var _ Client = &remoteClient{}
 
 type Client interface {
 	Run(command string, args ...string) error
@@ -225,7 +229,7 @@ func (c remoteClient) Endpoint() string {
 }
 
 // Close closes potentially initialized the SSH client.
-func (c remoteClient) Close() error {
+func (c *remoteClient) Close() error {
 	if !c.isInitialized() {
 		return nil
 	}
@@ -233,65 +237,210 @@ func (c remoteClient) Close() error {
 	return c.client.Close()
 }
 
-// Run establishes new connection with the remote host and executes
-// the given command.
-func (c remoteClient) Run(command string, args ...string) error {
-	return c.RunCtx(context.Background(), command)
+// Run establishes a connection with the remote host, if it has not been
+// established yet, and executes the given command.
+func (c *remoteClient) Run(command string, args ...string) error {
+	return c.RunCtx(context.Background(), command, args...)
+}
+
+// RunCtx establishes a connection with the remote host, if it has not been
+// established yet, and executes the given command. The command is
+// interrupted when the context is done.
+func (c *remoteClient) RunCtx(ctx context.Context, command string, args ...string) error {
+	return c.run(ctx, c.stdout, command, args...)
+}
+
+// Output executes the given command on the remote host and returns its
+// standard output as slice of bytes.
+func (c *remoteClient) Output(command string, args ...string) (stdout []byte, err error) {
+	return c.OutputCtx(context.Background(), command, args...)
 }
 
-// RunCtx establishes new connection with the remote host and executes
-// the given command.
-func (c remoteClient) RunCtx(ctx context.Context, command string, args ...string) error {
+// OutputCtx executes the given command on the remote host and returns its
+// standard output as slice of bytes.
+func (c *remoteClient) OutputCtx(ctx context.Context, command string, args ...string) (stdout []byte, err error) {
+	var buf bytes.Buffer
+	err = c.run(ctx, &buf, command, args...)
+	return buf.Bytes(), err
+}
+
+func (c *remoteClient) run(ctx context.Context, stdout io.Writer, command string, args ...string) error {
 	command, args = splitOneLineCommand(command, args)
 
+	session, closeSession, err := c.newSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeSession()
+
+	// Prepare command.
+	session.Stdin = c.stdin
+	session.Stdout = stdout
+	session.Stderr = c.stderr
+
+	cmd := command
+	if len(args) > 0 {
+		cmd = fmt.Sprintf("%s %s", command, strings.Join(args, " "))
+	}
+
+	// Environment variables are passed as a part of the command, since
+	// SSH servers commonly reject variables that are not explicitly
+	// accepted (AcceptEnv).
+	if envs := c.envAssignments(); envs != "" {
+		cmd = fmt.Sprintf("env %s %s", envs, cmd)
+	}
+
+	if c.sudo {
+		cmd = fmt.Sprintf("sudo %s", cmd)
+	}
+
+	err = session.Run(cmd)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// Shell opens an interactive shell on the remote host and waits until it
+// is closed. If the client's standard input is a terminal, the terminal is
+// put into raw mode and a pseudo terminal of the same size is requested
+// on the remote host.
+func (c *remoteClient) Shell(ctx context.Context) error {
+	session, closeSession, err := c.newSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeSession()
+
+	session.Stdin = c.stdin
+	session.Stdout = c.stdout
+	session.Stderr = c.stderr
+
+	if f, ok := c.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fd := int(f.Fd())
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("set terminal to raw mode: %v", err)
+		}
+		defer term.Restore(fd, state)
+
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = 80, 24
+		}
+
+		termType := os.Getenv("TERM")
+		if termType == "" {
+			termType = "xterm-256color"
+		}
+
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+
+		err = session.RequestPty(termType, height, width, modes)
+		if err != nil {
+			return fmt.Errorf("request pseudo terminal on %q: %v", c.Endpoint(), err)
+		}
+	}
+
+	if err := session.Shell(); err != nil {
+		return fmt.Errorf("start shell on %q: %v", c.Endpoint(), err)
+	}
+
+	err = session.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// newSession initiates a new SSH session, establishing the connection with
+// the remote host if it has not been established yet. The session is closed
+// when the context is done or when the returned function is called.
+func (c *remoteClient) newSession(ctx context.Context) (*ssh.Session, func(), error) {
 	// Ensure SSH client is initialized.
-	if c.client == nil {
+	if !c.isInitialized() {
 		err := c.initClient(ctx)
 		if err != nil {
-			c.mux.Unlock()
-			return err
+			return nil, nil, err
 		}
 	}
 
-	// Initiate new SSH session.
 	c.mux.Lock()
 	session, err := c.client.NewSession()
+	c.mux.Unlock()
 	if err != nil {
-		c.mux.Unlock()
-		return fmt.Errorf("create session for %q: %v", c.Endpoint(), err)
+		return nil, nil, fmt.Errorf("create session for %q: %v", c.Endpoint(), err)
 	}
-	defer session.Close()
-	c.mux.Unlock()
 
-	// Prepare command.
-	session.Stdin = c.stdin
-	session.Stdout = c.stdout
-	session.Stderr = c.stderr
+	done := make(chan struct{})
 
-	for k, v := range c.envs {
-		err := session.Setenv(k, v)
-		if err != nil {
-			return fmt.Errorf("set env variable %q: %v", k, err)
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.Close()
+		case <-done:
 		}
+	}()
+
+	closeSession := func() {
+		close(done)
+		session.Close()
 	}
 
-	// Run the command.
-	cmd := command
-	if len(args) > 0 {
-		cmd = fmt.Sprintf("%s %s", command, strings.Join(args, " "))
+	return session, closeSession, nil
+}
+
+// ExitStatus returns the exit status of the remote command, if the given
+// error is caused by the command exiting with a non-zero status.
+func ExitStatus(err error) (int, bool) {
+	var exitErr interface{ ExitStatus() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
 	}
 
-	if c.sudo {
-		cmd = fmt.Sprintf("sudo --preserve-env %s", cmd)
+	return 0, false
+}
+
+// envAssignments returns client's environment variables as quoted
+// assignments sorted by their names.
+func (c *remoteClient) envAssignments() string {
+	keys := make([]string, 0, len(c.envs))
+	for k := range c.envs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	envs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		envs = append(envs, fmt.Sprintf("%s=%s", k, ShellQuote(c.envs[k])))
 	}
 
-	return session.Run(cmd)
+	return strings.Join(envs, " ")
+}
+
+// ShellQuote quotes the string, so that the shell interprets it as a
+// single argument.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func (c *remoteClient) initClient(ctx context.Context) error {
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
+	// Client may have been initialized while waiting for the lock.
+	if c.initialized {
+		return nil
+	}
+
 	config := &ssh.ClientConfig{}
 	config.User = c.user
 
@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter is a writer that prefixes each line with the given prefix.
+// Incomplete lines are buffered until they are terminated or the writer is
+// flushed, and each line is written to the underlying writer at once.
+type PrefixWriter struct {
+	w      io.Writer
+	prefix []byte
+	buf    []byte
+}
+
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{
+		w:      w,
+		prefix: []byte(prefix),
+	}
+}
+
+func (w *PrefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the buffered incomplete line, if any, terminated with a
+// new line.
+func (w *PrefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	err := w.writeLine(append(w.buf, '\n'))
+	w.buf = nil
+
+	return err
+}
+
+func (w *PrefixWriter) writeLine(line []byte) error {
+	_, err := w.w.Write(append(append([]byte{}, w.prefix...), line...))
+	return err
+}
+
+// syncWriter is a writer that can be safely used by multiple goroutines.
+type syncWriter struct {
+	w   io.Writer
+	mux sync.Mutex
+}
+
+// NewSyncWriter returns a writer that serializes writes to the given
+// writer, which allows it to be shared among multiple goroutines.
+func NewSyncWriter(w io.Writer) io.Writer {
+	return &syncWriter{w: w}
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	return w.w.Write(p)
+}
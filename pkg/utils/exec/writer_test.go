@@ -0,0 +1,29 @@
+package exec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewPrefixWriter(&buf, "node | ")
+
+	_, err := w.Write([]byte("first\nsec"))
+	require.NoError(t, err)
+	assert.Equal(t, "node | first\n", buf.String())
+
+	_, err = w.Write([]byte("ond\nthird"))
+	require.NoError(t, err)
+	assert.Equal(t, "node | first\nnode | second\n", buf.String())
+
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "node | first\nnode | second\nnode | third\n", buf.String())
+
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "node | first\nnode | second\nnode | third\n", buf.String())
+}
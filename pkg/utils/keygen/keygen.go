@@ -1,6 +1,10 @@
 package keygen
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -12,6 +16,21 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
+// KeyType is a type of the SSH key.
+type KeyType string
+
+const (
+	RSA     KeyType = "rsa"
+	ECDSA   KeyType = "ecdsa"
+	ED25519 KeyType = "ed25519"
+)
+
+// Bit sizes used when the bit size of the generated key is not set.
+const (
+	DefaultRSABitSize   = 4096
+	DefaultECDSABitSize = 256
+)
+
 type (
 	Key interface {
 		Write(path string) error
@@ -31,17 +50,23 @@ func (k key) Write(path string) error {
 
 type (
 	KeyPair interface {
+		Type() KeyType
 		PublicKey() Key
 		PrivateKey() Key
 		Write(dir, keyName string) error
 	}
 
 	keyPair struct {
+		keyType KeyType
 		private key
 		public  key
 	}
 )
 
+func (p keyPair) Type() KeyType {
+	return p.keyType
+}
+
 func (p keyPair) PrivateKey() Key {
 	return p.private
 }
@@ -68,15 +93,53 @@ func (p keyPair) Write(dir, keyName string) error {
 	return p.PublicKey().Write(pubKeyPath)
 }
 
-// NewKeyPair generates new private and public key pair.
+// NewKeyPair generates new RSA private and public key pair.
 func NewKeyPair(bitSize int) (KeyPair, error) {
-	privateKey, err := generatePrivateKey(bitSize)
+	return NewKeyPairOfType(RSA, bitSize)
+}
+
+// NewKeyPairOfType generates new private and public key pair of the given
+// type. For RSA keys the bit size determines the key length, while for
+// ECDSA keys it selects the curve (256, 384 or 521). The bit size is
+// ignored for Ed25519 keys. If the type is empty, an RSA key is generated,
+// and if the bit size is zero, the default bit size of the type is used.
+func NewKeyPairOfType(keyType KeyType, bitSize int) (KeyPair, error) {
+	if keyType == "" {
+		keyType = RSA
+	}
+
+	var err error
+	var privateKey crypto.Signer
+
+	switch keyType {
+	case RSA:
+		if bitSize == 0 {
+			bitSize = DefaultRSABitSize
+		}
+
+		privateKey, err = generatePrivateKey(bitSize)
+	case ECDSA:
+		if bitSize == 0 {
+			bitSize = DefaultECDSABitSize
+		}
+
+		privateKey, err = generateEcdsaPrivateKey(bitSize)
+	case ED25519:
+		privateKey, err = generateEd25519PrivateKey()
+	default:
+		return nil, fmt.Errorf("generate private key: unsupported key type %q", keyType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	pair := keyPair{keyType: keyType}
+	pair.private.value, err = encodePrivateKey(privateKey)
 	if err != nil {
 		return nil, err
 	}
 
-	pair := keyPair{}
-	pair.private.value = encodePrivateKey(privateKey)
 	pair.public.value, err = generatePublicKey(privateKey)
 	if err != nil {
 		return nil, err
@@ -97,6 +160,11 @@ func ReadKeyPair(dir, keyName string) (KeyPair, error) {
 		return nil, NewKeyFileError("private", keyName, err)
 	}
 
+	pair.keyType, err = privateKeyType(pair.private.value)
+	if err != nil {
+		return nil, NewKeyFileError("private", keyName, err)
+	}
+
 	pubKeyName := keyName + ".pub"
 	pubKeyPath := path.Join(dir, pubKeyName)
 	pair.public.value, err = os.ReadFile(pubKeyPath)
@@ -108,10 +176,37 @@ func ReadKeyPair(dir, keyName string) (KeyPair, error) {
 }
 
 // Returns true if key pair with a given name exists in a
-// specified directory.
+// specified directory. Keys are not parsed, so an existing key
+// pair is never mistaken for a missing one.
 func KeyPairExists(dir, keyName string) bool {
-	kp, err := ReadKeyPair(dir, keyName)
-	return err == nil && kp != nil
+	for _, name := range []string{keyName, keyName + ".pub"} {
+		info, err := os.Stat(path.Join(dir, name))
+		if err != nil || info.IsDir() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// privateKeyType parses the PEM encoded private key and returns its type.
+// Keys in PKCS#1, PKCS#8, OpenSSL and OpenSSH formats are supported.
+func privateKeyType(pemBytes []byte) (KeyType, error) {
+	privateKey, err := ssh.ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		return "", err
+	}
+
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		return RSA, nil
+	case *ecdsa.PrivateKey:
+		return ECDSA, nil
+	case ed25519.PrivateKey, *ed25519.PrivateKey:
+		return ED25519, nil
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", privateKey)
+	}
 }
 
 // generatePrivateKey creates a RSA private key of specified bit size.
@@ -124,29 +219,85 @@ func generatePrivateKey(bitSize int) (*rsa.PrivateKey, error) {
 	return pk, pk.Validate()
 }
 
-// encodePrivateKey encodes Private Key from RSA to PEM format.
-func encodePrivateKey(privateKey *rsa.PrivateKey) []byte {
-	pemBlock := pem.Block{
-		Type:    "RSA PRIVATE KEY",
-		Headers: nil,
-		Bytes:   x509.MarshalPKCS1PrivateKey(privateKey),
+// generateEcdsaPrivateKey creates an ECDSA private key on the curve of
+// the specified bit size.
+func generateEcdsaPrivateKey(bitSize int) (*ecdsa.PrivateKey, error) {
+	var curve elliptic.Curve
+
+	switch bitSize {
+	case 256:
+		curve = elliptic.P256()
+	case 384:
+		curve = elliptic.P384()
+	case 521:
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("generate private key: invalid ECDSA bit size %d (supported: 256, 384, 521)", bitSize)
+	}
+
+	pk, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate private key: %v", err)
+	}
+
+	return pk, nil
+}
+
+// generateEd25519PrivateKey creates an Ed25519 private key.
+func generateEd25519PrivateKey() (ed25519.PrivateKey, error) {
+	_, pk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate private key: %v", err)
+	}
+
+	return pk, nil
+}
+
+// encodePrivateKey encodes the private key to PEM format. RSA and ECDSA
+// keys are encoded in PKCS#1 and SEC 1 formats respectively, while Ed25519
+// keys, which have no such format, are encoded in OpenSSH format.
+func encodePrivateKey(privateKey crypto.Signer) ([]byte, error) {
+	var pemBlock *pem.Block
+
+	switch pk := privateKey.(type) {
+	case *rsa.PrivateKey:
+		pemBlock = &pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(pk),
+		}
+	case *ecdsa.PrivateKey:
+		b, err := x509.MarshalECPrivateKey(pk)
+		if err != nil {
+			return nil, fmt.Errorf("encode private key: %v", err)
+		}
+
+		pemBlock = &pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: b,
+		}
+	default:
+		var err error
+		pemBlock, err = ssh.MarshalPrivateKey(privateKey, "")
+		if err != nil {
+			return nil, fmt.Errorf("encode private key: %v", err)
+		}
 	}
 
-	return pem.EncodeToMemory(&pemBlock)
+	return pem.EncodeToMemory(pemBlock), nil
 }
 
 // generatePublicKey generates a new public key from a public part of the
 // private key. The returned bytes are suitable for writing a .pub file,
-// since they are in format "ssh-rsa ...".
-func generatePublicKey(privateKey *rsa.PrivateKey) ([]byte, error) {
+// since they are in format "<type> ...", for example "ssh-rsa ...".
+func generatePublicKey(privateKey crypto.Signer) ([]byte, error) {
 	if privateKey == nil {
 		return nil, fmt.Errorf("generate public key: received <nil> private key")
 	}
 
-	publicRsaKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	publicKey, err := ssh.NewPublicKey(privateKey.Public())
 	if err != nil {
 		return nil, err
 	}
 
-	return ssh.MarshalAuthorizedKey(publicRsaKey), nil
+	return ssh.MarshalAuthorizedKey(publicKey), nil
 }
@@ -1,11 +1,15 @@
 package keygen
 
 import (
+	"encoding/pem"
 	"errors"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
+	"golang.org/x/crypto/ssh"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,7 +53,8 @@ func TestEncodePrivateKey(t *testing.T) {
 	key, err := generatePrivateKey(512)
 	require.NoError(t, err)
 
-	pem := encodePrivateKey(key)
+	pem, err := encodePrivateKey(key)
+	require.NoError(t, err)
 	assert.Contains(t, string(pem), "RSA PRIVATE KEY")
 }
 
@@ -72,6 +77,53 @@ func TestNewKeyPair(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewKeyPairOfType(t *testing.T) {
+	cases := []struct {
+		keyType    KeyType
+		bitSize    int
+		privateKey string
+		publicKey  string
+	}{
+		{"", 512, "RSA PRIVATE KEY", "ssh-rsa"},
+		{RSA, 512, "RSA PRIVATE KEY", "ssh-rsa"},
+		{ECDSA, 0, "EC PRIVATE KEY", "ecdsa-sha2-nistp256"},
+		{ECDSA, 384, "EC PRIVATE KEY", "ecdsa-sha2-nistp384"},
+		{ECDSA, 521, "EC PRIVATE KEY", "ecdsa-sha2-nistp521"},
+		{ED25519, 0, "OPENSSH PRIVATE KEY", "ssh-ed25519"},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.keyType), func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			kp, err := NewKeyPairOfType(c.keyType, c.bitSize)
+			require.NoError(t, err)
+			require.NoError(t, kp.Write(tmpDir, "key"))
+
+			privKey, err := os.ReadFile(path.Join(tmpDir, "key"))
+			require.NoError(t, err)
+			assert.Contains(t, string(privKey), c.privateKey)
+
+			pubKey, err := os.ReadFile(path.Join(tmpDir, "key.pub"))
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(pubKey), c.publicKey+" "))
+
+			// Generated key pair can be read back.
+			kp2, err := ReadKeyPair(tmpDir, "key")
+			require.NoError(t, err)
+			assert.Equal(t, kp, kp2)
+		})
+	}
+}
+
+func TestNewKeyPairOfType_Invalid(t *testing.T) {
+	_, err := NewKeyPairOfType("dsa", 0)
+	assert.EqualError(t, err, `generate private key: unsupported key type "dsa"`)
+
+	_, err = NewKeyPairOfType(ECDSA, 512)
+	assert.EqualError(t, err, "generate private key: invalid ECDSA bit size 512 (supported: 256, 384, 521)")
+}
+
 func TestReadKeyPair(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -85,6 +137,39 @@ func TestReadKeyPair(t *testing.T) {
 	assert.Equal(t, kp1, kp2)
 }
 
+func TestReadKeyPair_OpenSSH(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	key, err := generatePrivateKey(512)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(key, "user@host")
+	require.NoError(t, err)
+
+	pubKey, err := generatePublicKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path.Join(tmpDir, "key"), pem.EncodeToMemory(block), 0600))
+	require.NoError(t, os.WriteFile(path.Join(tmpDir, "key.pub"), pubKey, 0600))
+
+	kp, err := ReadKeyPair(tmpDir, "key")
+	require.NoError(t, err)
+	assert.Equal(t, RSA, kp.Type())
+}
+
+func TestReadKeyPair_InvalidPrivateKey(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(path.Join(tmpDir, "key"), []byte("invalid"), 0600))
+	require.NoError(t, os.WriteFile(path.Join(tmpDir, "key.pub"), []byte("invalid"), 0600))
+
+	_, err := ReadKeyPair(tmpDir, "key")
+	assert.EqualError(t, err, NewKeyFileError("private", "key", errors.New("ssh: no key found")).Error())
+
+	// Existing key pair is reported as existing, even if it is invalid.
+	assert.True(t, KeyPairExists(tmpDir, "key"))
+}
+
 func TestReadKeyPair_FailReadingPrivateKey(t *testing.T) {
 	tmpDir := t.TempDir()
 	keyName := "key"